@@ -0,0 +1,171 @@
+package event
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+var _ Event = &autoResetEventImpl{}
+
+// NewAutoResetEvent creates a new event that resets itself every time it releases a waiter,
+// like Win32 and .NET AutoResetEvent. Each Set releases exactly one waiter, or the next one to arrive
+// if nobody is waiting. Setting an event that is already set has no effect
+func NewAutoResetEvent(initValue bool) Event {
+	s := &autoResetEventImpl{
+		mutex: &sync.Mutex{},
+		flag:  initValue,
+		done:  make(chan struct{}),
+	}
+
+	if initValue {
+		close(s.done)
+	}
+
+	return s
+}
+
+type autoResetEventImpl struct {
+	mutex *sync.Mutex
+	flag  bool
+
+	// done is closed while a signal is pending
+	done chan struct{}
+
+	// waiters parked in Wait in arrival order, each one is released by closing its channel
+	waiters []chan struct{}
+
+	observers observers
+}
+
+func (s *autoResetEventImpl) IsSet() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.flag
+}
+
+// Set hands the signal to the first waiter or keeps it until a waiter arrives
+func (s *autoResetEventImpl) Set() {
+	s.mutex.Lock()
+
+	changed := false
+	if len(s.waiters) > 0 {
+		close(s.waiters[0])
+		s.waiters[0] = nil
+		s.waiters = s.waiters[1:]
+	} else if !s.flag {
+		s.flag = true
+		close(s.done)
+		changed = true
+	}
+
+	s.mutex.Unlock()
+
+	if changed {
+		s.observers.notify(EventChange{IsSet: true, Time: time.Now()})
+	}
+}
+
+// SetOne same as Set, an auto reset event never releases more than one waiter
+func (s *autoResetEventImpl) SetOne() {
+	s.Set()
+}
+
+// Done returns a channel that is closed while a signal is pending. Receiving from it does not consume
+// the signal, call Wait or WaitTimeout afterwards to take it
+func (s *autoResetEventImpl) Done() <-chan struct{} {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.done
+}
+
+// reset clears the flag. Returns true if the flag changed. Must be called with the lock held
+func (s *autoResetEventImpl) reset() bool {
+	if !s.flag {
+		return false
+	}
+
+	s.flag = false
+	s.done = make(chan struct{})
+	return true
+}
+
+// park takes the pending signal or registers a new waiter. Returns nil if the signal was taken
+func (s *autoResetEventImpl) park() chan struct{} {
+	s.mutex.Lock()
+
+	var w chan struct{}
+	consumed := s.reset()
+	if !consumed {
+		w = make(chan struct{})
+		s.waiters = append(s.waiters, w)
+	}
+
+	s.mutex.Unlock()
+
+	if consumed {
+		s.observers.notify(EventChange{IsSet: false, Time: time.Now()})
+	}
+
+	return w
+}
+
+// unpark removes a waiter that gave up. Returns false if it had already been released
+func (s *autoResetEventImpl) unpark(w chan struct{}) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i, other := range s.waiters {
+		if other == w {
+			s.waiters = append(s.waiters[:i], s.waiters[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *autoResetEventImpl) Wait() {
+	if w := s.park(); w != nil {
+		<-w
+	}
+}
+
+func (s *autoResetEventImpl) WaitTimeout(d time.Duration) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+
+	return s.WaitContext(ctx) == nil
+}
+
+func (s *autoResetEventImpl) WaitContext(ctx context.Context) error {
+	w := s.park()
+	if w == nil {
+		return nil
+	}
+
+	select {
+	case <-w:
+		return nil
+	case <-ctx.Done():
+		// Set may have picked this waiter in the meantime, the signal belongs to it now
+		if !s.unpark(w) {
+			return nil
+		}
+		return ctx.Err()
+	}
+}
+
+func (s *autoResetEventImpl) Reset() {
+	s.mutex.Lock()
+	changed := s.reset()
+	s.mutex.Unlock()
+
+	if changed {
+		s.observers.notify(EventChange{IsSet: false, Time: time.Now()})
+	}
+}
+
+func (s *autoResetEventImpl) observe(fn func(change EventChange)) func() {
+	return s.observers.add(fn)
+}
@@ -0,0 +1,146 @@
+package event
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutoResetWaitConsumes(t *testing.T) {
+	assert := assert.New(t)
+	ev := NewAutoResetEvent(false)
+
+	ev.Set()
+	assert.True(ev.IsSet())
+
+	// Setting twice doesn't accumulate signals
+	ev.Set()
+
+	assert.True(ev.WaitTimeout(0))
+	assert.False(ev.IsSet())
+	assert.False(ev.WaitTimeout(10 * time.Millisecond))
+}
+
+func TestAutoResetReleasesOne(t *testing.T) {
+	assert := assert.New(t)
+	ev := NewAutoResetEvent(false)
+
+	released := make(chan int, 2)
+	for i := 0; i < 2; i++ {
+		go func(i int) {
+			ev.Wait()
+			released <- i
+		}(i)
+	}
+
+	<-time.After(20 * time.Millisecond)
+
+	ev.Set()
+	<-released
+
+	select {
+	case <-released:
+		assert.Fail("Set must release a single waiter")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	// The signal went to the waiter, it is not pending anymore
+	assert.False(ev.IsSet())
+
+	ev.Set()
+	<-released
+}
+
+func TestAutoResetDone(t *testing.T) {
+	assert := assert.New(t)
+	ev := NewAutoResetEvent(false)
+
+	select {
+	case <-ev.Done():
+		assert.Fail("done must be open without a pending signal")
+	default:
+	}
+
+	ev.Set()
+	<-ev.Done()
+
+	// Done doesn't consume the signal
+	assert.True(ev.IsSet())
+	assert.Nil(ev.WaitContext(context.Background()))
+
+	select {
+	case <-ev.Done():
+		assert.Fail("done must be open once the signal is consumed")
+	default:
+	}
+}
+
+// Every Set is acknowledged before the next one, so no signal may be lost or delivered twice
+func TestAutoResetContention(t *testing.T) {
+	assert := assert.New(t)
+	ev := NewAutoResetEvent(false)
+
+	const waiters = 16
+	const signals = 2000
+
+	var wakeups int64
+	acks := make(chan struct{})
+	stop := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < waiters; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for {
+				var ok bool
+				if i%2 == 0 {
+					ok = ev.WaitContext(context.Background()) == nil
+				} else {
+					// Waiters giving up concurrently with Set must not swallow signals
+					ok = ev.WaitTimeout(time.Duration(i) * time.Microsecond)
+				}
+
+				select {
+				case <-stop:
+					return
+				default:
+				}
+
+				if ok {
+					atomic.AddInt64(&wakeups, 1)
+					acks <- struct{}{}
+				}
+			}
+		}(i)
+	}
+
+	for i := 0; i < signals; i++ {
+		ev.Set()
+
+		select {
+		case <-acks:
+		case <-time.After(5 * time.Second):
+			assert.FailNow("signal lost", "signal %d", i)
+		}
+	}
+
+	// No waiter got a duplicated signal
+	select {
+	case <-acks:
+		assert.Fail("signal duplicated")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	assert.Equal(int64(signals), atomic.LoadInt64(&wakeups))
+
+	close(stop)
+	for i := 0; i < waiters; i++ {
+		ev.Set()
+	}
+	wg.Wait()
+}
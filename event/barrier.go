@@ -0,0 +1,152 @@
+package event
+
+import (
+	"context"
+	"sync"
+)
+
+var _ CyclicBarrier = &cyclicBarrierImpl{}
+
+// NewCyclicBarrier creates a new barrier for the given number of parties. action, if not nil, is run by the last
+// party to arrive before the others are released. It must not call Await on the same barrier
+func NewCyclicBarrier(parties int, action func()) CyclicBarrier {
+	if parties < 1 {
+		parties = 1
+	}
+
+	return &cyclicBarrierImpl{
+		mutex:   &sync.Mutex{},
+		parties: parties,
+		action:  action,
+		gen:     newGeneration(),
+	}
+}
+
+// generation is one use of the barrier. done is closed when it is released or broken
+type generation struct {
+	done   chan struct{}
+	broken bool
+}
+
+func newGeneration() *generation {
+	return &generation{done: make(chan struct{})}
+}
+
+type cyclicBarrierImpl struct {
+	mutex   *sync.Mutex
+	parties int
+	action  func()
+
+	gen     *generation
+	waiting int
+}
+
+func (b *cyclicBarrierImpl) Await(ctx context.Context) (int, error) {
+	b.mutex.Lock()
+
+	gen := b.gen
+	if gen.broken {
+		b.mutex.Unlock()
+		return 0, ErrBrokenBarrier
+	}
+
+	if err := ctx.Err(); err != nil {
+		b.breakBarrier()
+		b.mutex.Unlock()
+		return 0, err
+	}
+
+	index := b.parties - 1 - b.waiting
+	b.waiting++
+
+	if index == 0 {
+		// Last one to arrive, the barrier can be reused right away while the action runs
+		b.gen = newGeneration()
+		b.waiting = 0
+		b.mutex.Unlock()
+
+		b.trip(gen)
+		return 0, nil
+	}
+
+	b.mutex.Unlock()
+
+	select {
+	case <-gen.done:
+	case <-ctx.Done():
+		b.mutex.Lock()
+		tripping := b.gen != gen
+		if !tripping {
+			b.breakBarrier()
+		}
+		b.mutex.Unlock()
+
+		if !tripping {
+			return 0, ctx.Err()
+		}
+
+		// The last party already arrived, wait the action to finish
+		<-gen.done
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if gen.broken {
+		return 0, ErrBrokenBarrier
+	}
+	return index, nil
+}
+
+// trip runs the action and releases the waiters of gen. A panicking action breaks gen
+func (b *cyclicBarrierImpl) trip(gen *generation) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.mutex.Lock()
+			gen.broken = true
+			b.mutex.Unlock()
+
+			close(gen.done)
+			panic(r)
+		}
+	}()
+
+	if b.action != nil {
+		b.action()
+	}
+
+	close(gen.done)
+}
+
+// breakBarrier breaks the current generation. Must be called with the lock held
+func (b *cyclicBarrierImpl) breakBarrier() {
+	if !b.gen.broken {
+		b.gen.broken = true
+		close(b.gen.done)
+	}
+}
+
+func (b *cyclicBarrierImpl) Reset() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.breakBarrier()
+	b.gen = newGeneration()
+	b.waiting = 0
+}
+
+func (b *cyclicBarrierImpl) IsBroken() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.gen.broken
+}
+
+func (b *cyclicBarrierImpl) Parties() int {
+	return b.parties
+}
+
+func (b *cyclicBarrierImpl) Waiting() int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.waiting
+}
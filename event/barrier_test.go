@@ -0,0 +1,111 @@
+package event
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func awaitAll(b CyclicBarrier, n int) ([]int, []error) {
+	indexes := make([]int, n)
+	errs := make([]error, n)
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			indexes[i], errs[i] = b.Await(context.Background())
+		}(i)
+	}
+	wg.Wait()
+
+	return indexes, errs
+}
+
+func TestBarrierAwait(t *testing.T) {
+	assert := assert.New(t)
+
+	var actions int32
+	b := NewCyclicBarrier(3, func() { atomic.AddInt32(&actions, 1) })
+	assert.Equal(3, b.Parties())
+
+	// Reusable across generations
+	for round := 1; round <= 3; round++ {
+		indexes, errs := awaitAll(b, 3)
+
+		sort.Ints(indexes)
+		assert.Equal([]int{0, 1, 2}, indexes)
+		assert.Equal([]error{nil, nil, nil}, errs)
+		assert.Equal(int32(round), atomic.LoadInt32(&actions))
+		assert.Equal(0, b.Waiting())
+	}
+}
+
+func TestBarrierActionBeforeRelease(t *testing.T) {
+	assert := assert.New(t)
+
+	var actionDone int32
+	b := NewCyclicBarrier(2, func() {
+		<-time.After(10 * time.Millisecond)
+		atomic.StoreInt32(&actionDone, 1)
+	})
+
+	go b.Await(context.Background())
+
+	_, err := b.Await(context.Background())
+	assert.Nil(err)
+	assert.Equal(int32(1), atomic.LoadInt32(&actionDone))
+}
+
+func TestBarrierBrokenByContext(t *testing.T) {
+	assert := assert.New(t)
+	b := NewCyclicBarrier(3, nil)
+
+	result := make(chan error)
+	go func() {
+		_, err := b.Await(context.Background())
+		result <- err
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := b.Await(ctx)
+	assert.Equal(context.DeadlineExceeded, err)
+	assert.Equal(ErrBrokenBarrier, <-result)
+	assert.True(b.IsBroken())
+
+	_, err = b.Await(context.Background())
+	assert.Equal(ErrBrokenBarrier, err)
+
+	b.Reset()
+	assert.False(b.IsBroken())
+
+	_, errs := awaitAll(b, 3)
+	assert.Equal([]error{nil, nil, nil}, errs)
+}
+
+func TestBarrierReset(t *testing.T) {
+	assert := assert.New(t)
+	b := NewCyclicBarrier(2, nil)
+
+	result := make(chan error)
+	go func() {
+		_, err := b.Await(context.Background())
+		result <- err
+	}()
+
+	<-time.After(10 * time.Millisecond)
+	assert.Equal(1, b.Waiting())
+
+	b.Reset()
+	assert.Equal(ErrBrokenBarrier, <-result)
+	assert.Equal(0, b.Waiting())
+	assert.False(b.IsBroken())
+}
@@ -0,0 +1,206 @@
+package bus
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/GustavoKatel/asyncutils/executor"
+	"github.com/GustavoKatel/asyncutils/queue"
+)
+
+var _ Bus = &busImpl{}
+
+// New creates a new bus. Without WithExecutor handlers run in the goroutine calling Publish, in subscription order
+func New(opts ...Option) Bus {
+	b := &busImpl{
+		mutex: &sync.RWMutex{},
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	return b
+}
+
+// SubscribeTyped same as Bus.Subscribe for payloads of type T, payloads of other types are skipped
+func SubscribeTyped[T any](b Bus, pattern string, handler func(ctx context.Context, topic string, payload T) error, opts ...SubscribeOption) func() {
+	return b.Subscribe(pattern, func(ctx context.Context, topic string, payload interface{}) error {
+		v, ok := payload.(T)
+		if !ok {
+			return nil
+		}
+		return handler(ctx, topic, v)
+	}, opts...)
+}
+
+type message struct {
+	topic   string
+	payload interface{}
+}
+
+type subscription struct {
+	pattern string
+	handler Handler
+
+	bufferSize int
+	policy     queue.OverflowPolicy
+
+	// buffer pending messages, only used with asynchronous delivery
+	buffer queue.BlockingQueue[message]
+
+	// draining is 1 while a job delivering the buffer is posted to the executor
+	draining int32
+	closed   int32
+}
+
+type busImpl struct {
+	mutex *sync.RWMutex
+	subs  []*subscription
+
+	exec    executor.Executor
+	onError func(topic string, err error)
+}
+
+func (b *busImpl) reportError(topic string, err error) {
+	if b.onError != nil {
+		b.onError(topic, err)
+	}
+}
+
+func (b *busImpl) Subscribe(pattern string, handler Handler, opts ...SubscribeOption) func() {
+	s := &subscription{
+		pattern:    pattern,
+		handler:    handler,
+		bufferSize: DefaultBufferSize,
+		policy:     queue.OverflowBlock,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if b.exec != nil {
+		s.buffer = queue.NewBoundedWithPolicy(s.bufferSize, s.policy, func(m message) {
+			b.reportError(m.topic, ErrMessageDropped)
+		})
+	}
+
+	b.mutex.Lock()
+	b.subs = append(b.subs, s)
+	b.mutex.Unlock()
+
+	once := &sync.Once{}
+	return func() {
+		once.Do(func() {
+			b.unsubscribe(s)
+		})
+	}
+}
+
+func (b *busImpl) unsubscribe(s *subscription) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for i, other := range b.subs {
+		if other == s {
+			b.subs = append(b.subs[:i:i], b.subs[i+1:]...)
+			break
+		}
+	}
+
+	// Pending messages are discarded
+	atomic.StoreInt32(&s.closed, 1)
+	if s.buffer != nil {
+		s.buffer.Clear()
+	}
+}
+
+func (b *busImpl) matching(topic string) []*subscription {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	subs := []*subscription{}
+	for _, s := range b.subs {
+		if Match(s.pattern, topic) {
+			subs = append(subs, s)
+		}
+	}
+
+	return subs
+}
+
+func (b *busImpl) Publish(ctx context.Context, topic string, payload interface{}) error {
+	var firstErr error
+
+	for _, s := range b.matching(topic) {
+		var err error
+		if b.exec == nil {
+			err = s.handler(ctx, topic, payload)
+		} else {
+			err = b.enqueue(ctx, s, message{topic: topic, payload: payload})
+		}
+
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+// enqueue buffers a message for s and makes sure a job is delivering its buffer
+func (b *busImpl) enqueue(ctx context.Context, s *subscription, m message) error {
+	if err := s.buffer.PushBackCtx(ctx, m); err != nil {
+		return err
+	}
+
+	return b.schedule(s)
+}
+
+// schedule posts a job delivering the buffer of s, unless there is one already
+func (b *busImpl) schedule(s *subscription) error {
+	if !atomic.CompareAndSwapInt32(&s.draining, 0, 1) {
+		return nil
+	}
+
+	err := b.exec.PostJob(func(ctx context.Context) error {
+		b.drain(ctx, s)
+		return nil
+	})
+
+	if err != nil {
+		// The messages stay buffered, the next Publish tries again
+		atomic.StoreInt32(&s.draining, 0)
+	}
+
+	return err
+}
+
+// drain delivers the buffer of s in order. A single job per subscriber keeps the messages ordered
+func (b *busImpl) drain(ctx context.Context, s *subscription) {
+	for {
+		for ctx.Err() == nil && atomic.LoadInt32(&s.closed) == 0 {
+			m, ok := s.buffer.PopFront()
+			if !ok {
+				break
+			}
+
+			if err := s.handler(ctx, m.topic, m.payload); err != nil {
+				b.reportError(m.topic, err)
+			}
+		}
+
+		atomic.StoreInt32(&s.draining, 0)
+
+		// A message may have been buffered after the last pop but before draining was cleared
+		if ctx.Err() != nil || s.buffer.Size() == 0 || !atomic.CompareAndSwapInt32(&s.draining, 0, 1) {
+			return
+		}
+	}
+}
+
+func (b *busImpl) Subscribers(topic string) int {
+	return len(b.matching(topic))
+}
@@ -0,0 +1,20 @@
+package bus
+
+import "context"
+
+// Handler handles a payload published on topic
+type Handler func(ctx context.Context, topic string, payload interface{}) error
+
+// Bus in-process publish/subscribe event bus
+type Bus interface {
+	// Subscribe registers a handler for a topic pattern. Topics are dot separated, in patterns "*" matches
+	// exactly one segment and "#" matches zero or more. Returns a function to unsubscribe
+	Subscribe(pattern string, handler Handler, opts ...SubscribeOption) func()
+
+	// Publish delivers payload to every subscriber of topic. Returns the first delivery error, the remaining
+	// subscribers still get the payload
+	Publish(ctx context.Context, topic string, payload interface{}) error
+
+	// Subscribers number of subscribers matching topic
+	Subscribers(topic string) int
+}
@@ -0,0 +1,204 @@
+package bus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GustavoKatel/asyncutils/executor"
+	"github.com/GustavoKatel/asyncutils/queue"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublishSync(t *testing.T) {
+	assert := assert.New(t)
+	b := New()
+
+	received := []string{}
+	unsubscribe := b.Subscribe("orders.*", func(ctx context.Context, topic string, payload interface{}) error {
+		received = append(received, topic+"="+payload.(string))
+		return nil
+	})
+
+	assert.Nil(b.Publish(context.Background(), "orders.created", "1"))
+	assert.Nil(b.Publish(context.Background(), "users.created", "2"))
+	assert.Nil(b.Publish(context.Background(), "orders.deleted", "3"))
+	assert.Equal([]string{"orders.created=1", "orders.deleted=3"}, received)
+	assert.Equal(1, b.Subscribers("orders.created"))
+
+	unsubscribe()
+	unsubscribe()
+
+	assert.Nil(b.Publish(context.Background(), "orders.created", "4"))
+	assert.Equal(2, len(received))
+	assert.Equal(0, b.Subscribers("orders.created"))
+}
+
+func TestPublishSyncErrors(t *testing.T) {
+	assert := assert.New(t)
+	b := New()
+	expected := errors.New("failed")
+
+	b.Subscribe("#", func(ctx context.Context, topic string, payload interface{}) error {
+		return expected
+	})
+
+	called := false
+	b.Subscribe("#", func(ctx context.Context, topic string, payload interface{}) error {
+		called = true
+		return nil
+	})
+
+	assert.Equal(expected, b.Publish(context.Background(), "a", nil))
+	assert.True(called)
+}
+
+func TestSubscribeTyped(t *testing.T) {
+	assert := assert.New(t)
+	b := New()
+
+	sum := 0
+	SubscribeTyped(b, "numbers", func(ctx context.Context, topic string, payload int) error {
+		sum += payload
+		return nil
+	})
+
+	assert.Nil(b.Publish(context.Background(), "numbers", 1))
+	assert.Nil(b.Publish(context.Background(), "numbers", "not a number"))
+	assert.Nil(b.Publish(context.Background(), "numbers", 2))
+	assert.Equal(3, sum)
+}
+
+func newExecutor(t *testing.T, workers int) executor.Executor {
+	exec, err := executor.NewDefaultExecutor(workers)
+	assert.Nil(t, err)
+	assert.Nil(t, exec.Start())
+	return exec
+}
+
+func TestPublishAsyncOrder(t *testing.T) {
+	assert := assert.New(t)
+
+	exec := newExecutor(t, 4)
+	defer exec.Stop()
+
+	b := New(WithExecutor(exec))
+
+	const count = 1000
+	results := make(chan []int, 2)
+
+	for i := 0; i < 2; i++ {
+		received := []int{}
+		b.Subscribe("numbers", func(ctx context.Context, topic string, payload interface{}) error {
+			received = append(received, payload.(int))
+			if len(received) == count {
+				results <- received
+			}
+			return nil
+		})
+	}
+
+	for i := 0; i < count; i++ {
+		assert.Nil(b.Publish(context.Background(), "numbers", i))
+	}
+
+	// Every subscriber gets all messages in publishing order
+	for i := 0; i < 2; i++ {
+		received := <-results
+		for j, v := range received {
+			assert.Equal(j, v)
+		}
+	}
+}
+
+func TestPublishAsyncSlowConsumer(t *testing.T) {
+	assert := assert.New(t)
+
+	exec := newExecutor(t, 2)
+	defer exec.Stop()
+
+	errs := make(chan error, 10)
+	b := New(WithExecutor(exec), WithErrorHandler(func(topic string, err error) {
+		errs <- err
+	}))
+
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	b.Subscribe("slow", func(ctx context.Context, topic string, payload interface{}) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}, WithBuffer(1, queue.OverflowReject))
+
+	fast := make(chan interface{}, 3)
+	b.Subscribe("slow", func(ctx context.Context, topic string, payload interface{}) error {
+		fast <- payload
+		return nil
+	})
+
+	// The first message is taken by the handler, the second fills the buffer
+	assert.Nil(b.Publish(context.Background(), "slow", 1))
+	<-started
+	assert.Nil(b.Publish(context.Background(), "slow", 2))
+
+	// The slow subscriber rejects, the other one still gets the message
+	assert.Equal(queue.ErrQueueFull, b.Publish(context.Background(), "slow", 3))
+
+	assert.Equal(1, <-fast)
+	assert.Equal(2, <-fast)
+	assert.Equal(3, <-fast)
+
+	close(release)
+	<-started
+	assert.Equal(0, len(errs))
+}
+
+func TestPublishAsyncDropped(t *testing.T) {
+	assert := assert.New(t)
+
+	// Not started, so the buffer fills up
+	exec, err := executor.NewDefaultExecutor(1)
+	assert.Nil(err)
+	defer exec.Stop()
+
+	errs := make(chan error, 10)
+	b := New(WithExecutor(exec), WithErrorHandler(func(topic string, err error) {
+		errs <- err
+	}))
+
+	received := make(chan interface{}, 10)
+	b.Subscribe("a", func(ctx context.Context, topic string, payload interface{}) error {
+		received <- payload
+		return errors.New("failed")
+	}, WithBuffer(1, queue.OverflowDropNewest))
+
+	assert.Nil(b.Publish(context.Background(), "a", 1))
+	assert.Nil(b.Publish(context.Background(), "a", 2))
+	assert.Equal(ErrMessageDropped, <-errs)
+
+	assert.Nil(exec.Start())
+
+	assert.Equal(1, <-received)
+	assert.Equal("failed", (<-errs).Error())
+}
+
+func TestPublishAsyncBlockContext(t *testing.T) {
+	assert := assert.New(t)
+
+	exec, err := executor.NewDefaultExecutor(1)
+	assert.Nil(err)
+	defer exec.Stop()
+
+	b := New(WithExecutor(exec))
+	b.Subscribe("a", func(ctx context.Context, topic string, payload interface{}) error {
+		return nil
+	}, WithBuffer(1, queue.OverflowBlock))
+
+	assert.Nil(b.Publish(context.Background(), "a", 1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.Equal(context.DeadlineExceeded, b.Publish(ctx, "a", 2))
+}
@@ -0,0 +1,8 @@
+package bus
+
+import "errors"
+
+var (
+	// ErrMessageDropped a message was dropped by the subscriber overflow policy
+	ErrMessageDropped = errors.New("Message dropped by the subscriber overflow policy")
+)
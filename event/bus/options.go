@@ -0,0 +1,40 @@
+package bus
+
+import (
+	"github.com/GustavoKatel/asyncutils/executor"
+	"github.com/GustavoKatel/asyncutils/queue"
+)
+
+// DefaultBufferSize default number of pending messages per subscriber with asynchronous delivery
+const DefaultBufferSize = 1024
+
+// Option configures a bus
+type Option func(b *busImpl)
+
+// WithExecutor delivers messages asynchronously through exec instead of in the goroutine calling Publish.
+// Each subscriber gets its own buffer and receives its messages in publishing order
+func WithExecutor(exec executor.Executor) Option {
+	return func(b *busImpl) {
+		b.exec = exec
+	}
+}
+
+// WithErrorHandler receives the errors of asynchronous deliveries, including ErrMessageDropped
+func WithErrorHandler(fn func(topic string, err error)) Option {
+	return func(b *busImpl) {
+		b.onError = fn
+	}
+}
+
+// SubscribeOption configures a subscription
+type SubscribeOption func(s *subscription)
+
+// WithBuffer sets the size of the subscriber buffer used by asynchronous delivery and what happens when it is full.
+// With queue.OverflowBlock Publish waits for free space, with queue.OverflowReject it returns queue.ErrQueueFull.
+// A size lower or equal to zero creates an unbounded buffer
+func WithBuffer(size int, policy queue.OverflowPolicy) SubscribeOption {
+	return func(s *subscription) {
+		s.bufferSize = size
+		s.policy = policy
+	}
+}
@@ -0,0 +1,42 @@
+package bus
+
+import "strings"
+
+const (
+	// WildcardOne matches exactly one topic segment
+	WildcardOne = "*"
+
+	// WildcardMany matches zero or more topic segments
+	WildcardMany = "#"
+)
+
+// Match returns true if topic matches pattern
+func Match(pattern string, topic string) bool {
+	return matchSegments(strings.Split(pattern, "."), strings.Split(topic, "."))
+}
+
+func matchSegments(pattern []string, topic []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == WildcardMany {
+			for i := 0; i <= len(topic); i++ {
+				if matchSegments(pattern[1:], topic[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(topic) == 0 {
+			return false
+		}
+
+		if pattern[0] != WildcardOne && pattern[0] != topic[0] {
+			return false
+		}
+
+		pattern = pattern[1:]
+		topic = topic[1:]
+	}
+
+	return len(topic) == 0
+}
@@ -0,0 +1,28 @@
+package bus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(Match("orders.created", "orders.created"))
+	assert.False(Match("orders.created", "orders.deleted"))
+	assert.False(Match("orders", "orders.created"))
+
+	assert.True(Match("orders.*", "orders.created"))
+	assert.False(Match("orders.*", "orders"))
+	assert.False(Match("orders.*", "orders.created.eu"))
+	assert.True(Match("*.created", "users.created"))
+
+	assert.True(Match("orders.#", "orders"))
+	assert.True(Match("orders.#", "orders.created.eu"))
+	assert.True(Match("#", "anything.at.all"))
+	assert.True(Match("#.eu", "orders.created.eu"))
+	assert.False(Match("#.eu", "orders.created.us"))
+	assert.True(Match("orders.#.eu", "orders.eu"))
+	assert.True(Match("orders.*.#", "orders.created"))
+}
@@ -0,0 +1,161 @@
+package event
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// WaitAny waits any of the waiters to be released or ctx to be done. Returns the index of the released waiter,
+// or -1 and ctx.Err() if ctx was done first. It only watches Done, so it doesn't consume auto reset signals
+func WaitAny(ctx context.Context, waiters ...EventWaiter) (int, error) {
+	cases := make([]reflect.SelectCase, 0, len(waiters)+1)
+	cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())})
+
+	for _, w := range waiters {
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(w.Done())})
+	}
+
+	chosen, _, _ := reflect.Select(cases)
+	if chosen == 0 {
+		return -1, ctx.Err()
+	}
+
+	return chosen - 1, nil
+}
+
+// WaitAll waits all the waiters to be released or ctx to be done. Returns ctx.Err() if ctx was done first
+func WaitAll(ctx context.Context, waiters ...EventWaiter) error {
+	for _, w := range waiters {
+		select {
+		case <-w.Done():
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+var _ DerivedEvent = &derivedEventImpl{}
+
+// Or creates a live event that is set while any of the events is set. Set and Reset are forwarded to all of them.
+// Events created outside this package are only checked by IsSet, their changes don't release waiters.
+// The inputs keep a reference to it until Detach is called
+func Or(events ...Event) DerivedEvent {
+	return newDerivedEvent(events, false)
+}
+
+// And creates a live event that is set while all the events are set. Set and Reset are forwarded to all of them.
+// Events created outside this package are only checked by IsSet, their changes don't release waiters.
+// The inputs keep a reference to it until Detach is called
+func And(events ...Event) DerivedEvent {
+	return newDerivedEvent(events, true)
+}
+
+// derivedEventImpl mirrors the combined state of its inputs in ev, updated every time one of them changes
+type derivedEventImpl struct {
+	mutex  *sync.Mutex
+	inputs []Event
+	all    bool
+	ev     *eventImpl
+
+	// unobserve removes the observers registered on the inputs
+	unobserve  []func()
+	detachOnce *sync.Once
+}
+
+func newDerivedEvent(inputs []Event, all bool) *derivedEventImpl {
+	d := &derivedEventImpl{
+		mutex:      &sync.Mutex{},
+		inputs:     inputs,
+		all:        all,
+		detachOnce: &sync.Once{},
+	}
+
+	d.ev = NewEvent(d.IsSet()).(*eventImpl)
+
+	for _, in := range inputs {
+		if o, ok := in.(observable); ok {
+			d.unobserve = append(d.unobserve, o.observe(func(EventChange) { d.update() }))
+		}
+	}
+
+	// An input may have changed before its observer was registered
+	d.update()
+
+	return d
+}
+
+// update evaluates the inputs and applies the result to ev. The lock keeps concurrent updates from applying
+// a stale result last
+func (d *derivedEventImpl) update() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.IsSet() {
+		d.ev.Set()
+	} else {
+		d.ev.Reset()
+	}
+}
+
+func (d *derivedEventImpl) Detach() {
+	d.detachOnce.Do(func() {
+		for _, unobserve := range d.unobserve {
+			unobserve()
+		}
+	})
+}
+
+func (d *derivedEventImpl) IsSet() bool {
+	for _, in := range d.inputs {
+		if in.IsSet() != d.all {
+			return !d.all
+		}
+	}
+
+	return d.all
+}
+
+// Set sets all the inputs
+func (d *derivedEventImpl) Set() {
+	for _, in := range d.inputs {
+		in.Set()
+	}
+}
+
+// SetOne calls SetOne on all the inputs
+func (d *derivedEventImpl) SetOne() {
+	for _, in := range d.inputs {
+		in.SetOne()
+	}
+}
+
+// Reset resets all the inputs
+func (d *derivedEventImpl) Reset() {
+	for _, in := range d.inputs {
+		in.Reset()
+	}
+}
+
+func (d *derivedEventImpl) Wait() {
+	d.ev.Wait()
+}
+
+func (d *derivedEventImpl) WaitTimeout(timeout time.Duration) bool {
+	return d.ev.WaitTimeout(timeout)
+}
+
+func (d *derivedEventImpl) WaitContext(ctx context.Context) error {
+	return d.ev.WaitContext(ctx)
+}
+
+func (d *derivedEventImpl) Done() <-chan struct{} {
+	return d.ev.Done()
+}
+
+func (d *derivedEventImpl) observe(fn func(change EventChange)) func() {
+	return d.ev.observe(fn)
+}
@@ -0,0 +1,184 @@
+package event
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitAny(t *testing.T) {
+	assert := assert.New(t)
+	shutdown := NewEvent(false)
+	data := NewEvent(false)
+
+	go func() {
+		<-time.After(10 * time.Millisecond)
+		data.Set()
+	}()
+
+	index, err := WaitAny(context.Background(), shutdown, data)
+	assert.Nil(err)
+	assert.Equal(1, index)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	index, err = WaitAny(ctx, shutdown, NewCountDownLatch(1))
+	assert.Equal(context.DeadlineExceeded, err)
+	assert.Equal(-1, index)
+}
+
+func TestWaitAll(t *testing.T) {
+	assert := assert.New(t)
+	a := NewEvent(false)
+	b := NewCountDownLatch(2)
+
+	go func() {
+		<-time.After(10 * time.Millisecond)
+		b.CountDown()
+		a.Set()
+		b.CountDown()
+	}()
+
+	assert.Nil(WaitAll(context.Background(), a, b))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.Equal(context.DeadlineExceeded, WaitAll(ctx, a, NewEvent(false)))
+}
+
+func TestOr(t *testing.T) {
+	assert := assert.New(t)
+	a := NewEvent(false)
+	b := NewEvent(false)
+	or := Or(a, b)
+
+	assert.False(or.IsSet())
+	assert.False(or.WaitTimeout(10 * time.Millisecond))
+
+	go func() {
+		<-time.After(10 * time.Millisecond)
+		b.Set()
+	}()
+
+	or.Wait()
+	assert.True(or.IsSet())
+
+	// Follows the inputs back
+	b.Reset()
+	assert.False(or.IsSet())
+	assert.False(or.WaitTimeout(0))
+
+	a.Set()
+	<-or.Done()
+
+	or.Reset()
+	assert.False(a.IsSet())
+	assert.False(b.IsSet())
+}
+
+func TestDerivedDetach(t *testing.T) {
+	assert := assert.New(t)
+	a := NewEvent(false)
+	b := NewEvent(false)
+
+	observed := func(ev Event) int {
+		o := &ev.(*eventImpl).observers
+		o.mutex.Lock()
+		defer o.mutex.Unlock()
+		return len(o.fns)
+	}
+
+	for i := 0; i < 10; i++ {
+		or := Or(a, b)
+		assert.Equal(1, observed(a))
+
+		or.Detach()
+		or.Detach()
+		assert.Equal(0, observed(a))
+		assert.Equal(0, observed(b))
+	}
+
+	// Detached events no longer follow the inputs, IsSet still reads them
+	or := Or(a, b)
+	or.Detach()
+	a.Set()
+	assert.True(or.IsSet())
+	assert.False(or.WaitTimeout(10 * time.Millisecond))
+}
+
+func TestAnd(t *testing.T) {
+	assert := assert.New(t)
+	a := NewEvent(true)
+	b := NewEvent(false)
+	and := And(a, b)
+
+	assert.False(and.IsSet())
+
+	b.Set()
+	assert.True(and.WaitTimeout(time.Second))
+
+	a.Reset()
+	assert.False(and.IsSet())
+	assert.False(and.WaitTimeout(0))
+
+	and.Set()
+	assert.True(a.IsSet())
+	assert.True(b.IsSet())
+	assert.True(and.WaitTimeout(0))
+}
+
+func TestDerivedNested(t *testing.T) {
+	assert := assert.New(t)
+	a := NewEvent(false)
+	b := NewEvent(false)
+	c := NewAutoResetEvent(false)
+
+	ev := Or(And(a, b), c)
+
+	a.Set()
+	assert.False(ev.WaitTimeout(0))
+
+	b.Set()
+	assert.True(ev.WaitTimeout(0))
+
+	a.Reset()
+	assert.False(ev.WaitTimeout(0))
+
+	// A pending auto reset signal sets the derived event until it is consumed
+	c.Set()
+	assert.True(ev.WaitTimeout(0))
+	assert.True(c.WaitTimeout(0))
+	assert.False(ev.WaitTimeout(0))
+}
+
+func TestDerivedConcurrent(t *testing.T) {
+	assert := assert.New(t)
+	a := NewEvent(false)
+	b := NewEvent(false)
+	or := Or(a, b)
+
+	var wg sync.WaitGroup
+	for _, ev := range []Event{a, b} {
+		wg.Add(1)
+		go func(ev Event) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				ev.Set()
+				ev.Reset()
+			}
+		}(ev)
+	}
+	wg.Wait()
+
+	// Whatever the interleaving, the derived state settles on the inputs
+	assert.False(or.IsSet())
+	assert.False(or.WaitTimeout(0))
+
+	a.Set()
+	assert.True(or.WaitTimeout(0))
+}
@@ -0,0 +1,11 @@
+package event
+
+import "errors"
+
+var (
+	// ErrBrokenBarrier a party gave up waiting or the barrier was reset
+	ErrBrokenBarrier = errors.New("Barrier is broken")
+
+	// ErrNilRejection a promise was rejected with a nil error
+	ErrNilRejection = errors.New("Promise rejected with a nil error")
+)
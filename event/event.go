@@ -6,77 +6,253 @@ import (
 	"time"
 )
 
-var _ Event = &eventImpl{}
+var _ InstrumentedEvent = &eventImpl{}
 
 // NewEvent creates a new sync event flag
-func NewEvent(initValue bool) Event {
-	mutex := &sync.RWMutex{}
+func NewEvent(initValue bool, opts ...Option) Event {
+	return newEvent(initValue, opts...)
+}
+
+// NewInstrumentedEvent same as NewEvent with stats enabled, see WithStats
+func NewInstrumentedEvent(initValue bool, opts ...Option) InstrumentedEvent {
+	return newEvent(initValue, append([]Option{WithStats()}, opts...)...)
+}
+
+func newEvent(initValue bool, opts ...Option) *eventImpl {
+	s := &eventImpl{
+		mutex: &sync.Mutex{},
+		flag:  initValue,
+		done:  make(chan struct{}),
+	}
+
+	if initValue {
+		close(s.done)
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
 
-	return &eventImpl{
-		flagMutex: mutex,
-		flag:      initValue,
-		cond:      sync.NewCond(mutex.RLocker()),
+	if s.name != "" {
+		register(s.name, s)
 	}
+
+	return s
 }
 
 type eventImpl struct {
-	flagMutex *sync.RWMutex
-	flag      bool
-	cond      *sync.Cond
+	mutex *sync.Mutex
+	flag  bool
+
+	// done is closed while the flag is set, Reset replaces it with a new one
+	done chan struct{}
+
+	// waiters parked in Wait in arrival order, each one is released by closing its channel
+	waiters []chan struct{}
+
+	observers observers
+
+	name string
+
+	// stats enables the counters below, see WithStats
+	stats       bool
+	sets        uint64
+	resets      uint64
+	lastSet     time.Time
+	lastReset   time.Time
+	lastSetBy   string
+	lastResetBy string
 }
 
 func (s *eventImpl) IsSet() bool {
-	s.flagMutex.RLock()
-	defer s.flagMutex.RUnlock()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
 	return s.flag
 }
 
-func (s *eventImpl) setFlag() {
-	s.flagMutex.Lock()
-	defer s.flagMutex.Unlock()
+// caller returns the caller of the Set or Reset method calling it, only if stats are enabled
+func (s *eventImpl) caller() string {
+	if !s.stats {
+		return ""
+	}
+	return callerName(3)
+}
+
+// recordSet updates the stats of a Set call. Must be called with the lock held
+func (s *eventImpl) recordSet(now time.Time, caller string) {
+	if s.stats {
+		s.sets++
+		s.lastSet = now
+		s.lastSetBy = caller
+	}
+}
+
+// setFlag sets the flag and closes done. Returns true if the flag changed. Must be called with the lock held
+func (s *eventImpl) setFlag() bool {
+	if s.flag {
+		return false
+	}
+
 	s.flag = true
+	close(s.done)
+	return true
 }
 
 func (s *eventImpl) Set() {
-	s.setFlag()
-	s.cond.Broadcast()
+	caller := s.caller()
+	now := time.Now()
+
+	s.mutex.Lock()
+
+	s.recordSet(now, caller)
+	changed := s.setFlag()
+
+	for _, w := range s.waiters {
+		close(w)
+	}
+	s.waiters = nil
+
+	s.mutex.Unlock()
+
+	if changed {
+		s.observers.notify(EventChange{Name: s.name, IsSet: true, Time: now, Caller: caller})
+	}
 }
 
 func (s *eventImpl) SetOne() {
-	s.setFlag()
-	s.cond.Signal()
+	caller := s.caller()
+	now := time.Now()
+
+	s.mutex.Lock()
+
+	s.recordSet(now, caller)
+	changed := s.setFlag()
+
+	if len(s.waiters) > 0 {
+		close(s.waiters[0])
+		s.waiters[0] = nil
+		s.waiters = s.waiters[1:]
+	}
+
+	s.mutex.Unlock()
+
+	if changed {
+		s.observers.notify(EventChange{Name: s.name, IsSet: true, Time: now, Caller: caller})
+	}
 }
 
-func (s *eventImpl) Wait() {
-	s.cond.L.Lock()
-	defer s.cond.L.Unlock()
+func (s *eventImpl) Done() <-chan struct{} {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.done
+}
 
-	for !s.flag {
-		s.cond.Wait()
+// park registers a new waiter. Returns nil if the flag is already set
+func (s *eventImpl) park() chan struct{} {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.flag {
+		return nil
 	}
+
+	w := make(chan struct{})
+	s.waiters = append(s.waiters, w)
+	return w
 }
 
-func (s *eventImpl) WaitTimeout(d time.Duration) {
-	s.cond.L.Lock()
-	defer s.cond.L.Unlock()
+// unpark removes a waiter that gave up. Returns false if it had already been released
+func (s *eventImpl) unpark(w chan struct{}) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-	go func() {
-		defer cancel()
-		for !s.flag && ctx.Err() == nil {
-			s.cond.Wait()
+	for i, other := range s.waiters {
+		if other == w {
+			s.waiters = append(s.waiters[:i], s.waiters[i+1:]...)
+			return true
 		}
-	}()
+	}
+
+	return false
+}
+
+func (s *eventImpl) Wait() {
+	if w := s.park(); w != nil {
+		<-w
+	}
+}
+
+func (s *eventImpl) WaitTimeout(d time.Duration) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+
+	return s.WaitContext(ctx) == nil
+}
+
+func (s *eventImpl) WaitContext(ctx context.Context) error {
+	w := s.park()
+	if w == nil {
+		return nil
+	}
 
 	select {
+	case <-w:
+		return nil
 	case <-ctx.Done():
-	case <-time.After(d):
+		// SetOne may have picked this waiter in the meantime, don't lose its signal
+		if !s.unpark(w) {
+			return nil
+		}
+		return ctx.Err()
 	}
 }
 
 func (s *eventImpl) Reset() {
-	s.flagMutex.Lock()
-	defer s.flagMutex.Unlock()
-	s.flag = false
+	caller := s.caller()
+	now := time.Now()
+
+	s.mutex.Lock()
+
+	if s.stats {
+		s.resets++
+		s.lastReset = now
+		s.lastResetBy = caller
+	}
+
+	changed := s.flag
+	if changed {
+		s.flag = false
+		s.done = make(chan struct{})
+	}
+
+	s.mutex.Unlock()
+
+	if changed {
+		s.observers.notify(EventChange{Name: s.name, IsSet: false, Time: now, Caller: caller})
+	}
+}
+
+func (s *eventImpl) Stats() EventStats {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return EventStats{
+		Name:        s.name,
+		IsSet:       s.flag,
+		Waiters:     len(s.waiters),
+		Sets:        s.sets,
+		Resets:      s.resets,
+		LastSet:     s.lastSet,
+		LastReset:   s.lastReset,
+		LastSetBy:   s.lastSetBy,
+		LastResetBy: s.lastResetBy,
+	}
+}
+
+func (s *eventImpl) OnChange(fn func(change EventChange)) func() {
+	return s.observers.add(fn)
+}
+
+func (s *eventImpl) observe(fn func(change EventChange)) func() {
+	return s.observers.add(fn)
 }
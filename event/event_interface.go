@@ -1,13 +1,22 @@
 package event
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 type EventWaiter interface {
 	// Wait waits this flag to be set
 	Wait()
 
-	// WaitTimeout waits this flag to be set or timeout
-	WaitTimeout(d time.Duration)
+	// WaitTimeout waits this flag to be set or timeout. Returns true if the flag was set
+	WaitTimeout(d time.Duration) bool
+
+	// WaitContext waits this flag to be set or ctx to be done. Returns ctx.Err() if ctx was done first
+	WaitContext(ctx context.Context) error
+
+	// Done returns a channel that is closed when this flag is set, to be used in select statements
+	Done() <-chan struct{}
 }
 
 // Event synchronizes goroutines with a set-reset flag style
@@ -23,6 +32,130 @@ type Event interface {
 	// SetOne sets the flag to true and awake only one pending goroutines
 	SetOne()
 
-	// Reset resets this flag
+	// Reset resets this flag. Channels returned by Done before the reset stay closed, call Done again to get a new one
 	Reset()
 }
+
+// DerivedEvent is an event whose state follows other events, see Or and And
+type DerivedEvent interface {
+	Event
+
+	// Detach stops following the inputs so the derived event can be garbage collected while they are alive.
+	// Waiters are no longer released by the inputs, IsSet still reads them
+	Detach()
+}
+
+// CountDownLatch is released once CountDown has been called n times
+type CountDownLatch interface {
+	EventWaiter
+
+	// CountDown decrements the count, releasing all waiters when it reaches zero
+	CountDown()
+
+	// Count returns the current count
+	Count() int
+}
+
+// CyclicBarrier makes a fixed number of goroutines wait for each other. It can be reused once released
+type CyclicBarrier interface {
+	// Await waits all parties to arrive. Returns the arrival index, parties-1 for the first and 0 for the last.
+	// Returns ErrBrokenBarrier if another party gave up or Reset was called, or ctx.Err() if ctx was done first
+	Await(ctx context.Context) (int, error)
+
+	// Reset breaks the current generation, releasing its waiters with ErrBrokenBarrier, and starts a new one
+	Reset()
+
+	// IsBroken returns true if the current generation is broken
+	IsBroken() bool
+
+	// Parties returns the number of parties needed to release the barrier
+	Parties() int
+
+	// Waiting returns the number of parties waiting in the current generation
+	Waiting() int
+}
+
+// Future is the read side of a value that will be available later. It is released when the value or an error is set
+type Future[T any] interface {
+	EventWaiter
+
+	// Get waits the future to complete or ctx to be done. Returns ctx.Err() if ctx was done first
+	Get(ctx context.Context) (T, error)
+
+	// TryGet returns the result without waiting. ok is false if the future is not complete yet
+	TryGet() (v T, ok bool, err error)
+}
+
+// Promise is the write side of a Future. Only the first call to Resolve or Reject has effect
+type Promise[T any] interface {
+	Future[T]
+
+	// Resolve completes the future with a value. Returns false if it was already complete
+	Resolve(v T) bool
+
+	// Reject completes the future with an error, ErrNilRejection if err is nil. Returns false if it was already complete
+	Reject(err error) bool
+
+	// Future returns the read only side of this promise
+	Future() Future[T]
+}
+
+// Watchable holds a value read by many goroutines, which can wait for it to change. Every Store creates a new version
+type Watchable[T any] interface {
+	// Load returns the current value and its version
+	Load() (T, uint64)
+
+	// Store replaces the value, releasing the goroutines waiting in Changed. Returns the new version
+	Store(v T) uint64
+
+	// CompareAndSwap stores v only if the current version is "version". Returns the new version and true if stored
+	CompareAndSwap(version uint64, v T) (uint64, bool)
+
+	// Changed waits for a version newer than "sinceVersion" or ctx to be done. Returns the current value and version,
+	// and ctx.Err() if ctx was done first
+	Changed(ctx context.Context, sinceVersion uint64) (T, uint64, error)
+}
+
+// EventStats diagnostics of an event. The counters and the last calls are only recorded with WithStats
+type EventStats struct {
+	Name  string
+	IsSet bool
+
+	// Waiters goroutines parked waiting the event
+	Waiters int
+
+	// Sets calls to Set and SetOne
+	Sets uint64
+
+	// Resets calls to Reset
+	Resets uint64
+
+	// LastSet time of the last call to Set or SetOne, LastSetBy its caller as file:line
+	LastSet   time.Time
+	LastSetBy string
+
+	// LastReset time of the last call to Reset, LastResetBy its caller as file:line
+	LastReset   time.Time
+	LastResetBy string
+}
+
+// EventChange describes a state transition of an event
+type EventChange struct {
+	Name  string
+	IsSet bool
+	Time  time.Time
+
+	// Caller of the method that changed the state as file:line, only recorded with WithStats
+	Caller string
+}
+
+// InstrumentedEvent event exposing diagnostics and state change callbacks
+type InstrumentedEvent interface {
+	Event
+
+	// Stats returns the diagnostics of this event
+	Stats() EventStats
+
+	// OnChange registers fn to be called after every state transition. Returns a function to remove it
+	OnChange(fn func(change EventChange)) func()
+}
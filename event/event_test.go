@@ -0,0 +1,175 @@
+package event
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetWait(t *testing.T) {
+	assert := assert.New(t)
+	ev := NewEvent(false)
+
+	assert.False(ev.IsSet())
+
+	go func() {
+		<-time.After(10 * time.Millisecond)
+		ev.Set()
+	}()
+
+	ev.Wait()
+	assert.True(ev.IsSet())
+
+	ev.Reset()
+	assert.False(ev.IsSet())
+}
+
+func TestWaitTimeout(t *testing.T) {
+	assert := assert.New(t)
+	ev := NewEvent(false)
+
+	assert.False(ev.WaitTimeout(10 * time.Millisecond))
+
+	go func() {
+		<-time.After(10 * time.Millisecond)
+		ev.Set()
+	}()
+
+	assert.True(ev.WaitTimeout(time.Second))
+	assert.True(ev.WaitTimeout(0))
+}
+
+func TestWaitContext(t *testing.T) {
+	assert := assert.New(t)
+	ev := NewEvent(false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-time.After(10 * time.Millisecond)
+		cancel()
+	}()
+
+	assert.Equal(context.Canceled, ev.WaitContext(ctx))
+
+	ev.Set()
+	assert.Nil(ev.WaitContext(context.Background()))
+	assert.Nil(ev.WaitContext(ctx))
+}
+
+func TestWaitTimeoutNoLeak(t *testing.T) {
+	assert := assert.New(t)
+	ev := NewEvent(false)
+
+	before := runtime.NumGoroutine()
+
+	for i := 0; i < 100; i++ {
+		assert.False(ev.WaitTimeout(time.Millisecond))
+	}
+
+	// Give the helper goroutines some time to leave
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		<-time.After(10 * time.Millisecond)
+	}
+
+	assert.LessOrEqual(runtime.NumGoroutine(), before)
+}
+
+func TestDone(t *testing.T) {
+	assert := assert.New(t)
+	ev := NewEvent(false)
+
+	done := ev.Done()
+	select {
+	case <-done:
+		assert.Fail("done must be open while the flag is not set")
+	default:
+	}
+
+	go func() {
+		<-time.After(10 * time.Millisecond)
+		ev.Set()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		assert.Fail("done was not closed by Set")
+	}
+
+	ev.Reset()
+
+	// The old channel stays closed, a new open one replaces it
+	_, ok := <-done
+	assert.False(ok)
+
+	select {
+	case <-ev.Done():
+		assert.Fail("done must be open after Reset")
+	default:
+	}
+
+	ev.SetOne()
+	_, ok = <-ev.Done()
+	assert.False(ok)
+}
+
+func TestDoneInitSet(t *testing.T) {
+	ev := NewEvent(true)
+	<-ev.Done()
+}
+
+func TestSetOneReleasesOne(t *testing.T) {
+	assert := assert.New(t)
+	ev := NewEvent(false)
+
+	released := make(chan int, 2)
+	for i := 0; i < 2; i++ {
+		go func(i int) {
+			ev.Wait()
+			released <- i
+		}(i)
+	}
+
+	// Let both goroutines park
+	<-time.After(20 * time.Millisecond)
+
+	ev.SetOne()
+	<-released
+
+	select {
+	case <-released:
+		assert.Fail("SetOne must release a single waiter")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	// New waiters see the flag set
+	assert.True(ev.WaitTimeout(0))
+
+	ev.Set()
+	<-released
+}
+
+func TestWaitContextKeepsSignal(t *testing.T) {
+	assert := assert.New(t)
+	ev := NewEvent(false).(*eventImpl)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	result := make(chan error)
+	go func() {
+		result <- ev.WaitContext(ctx)
+	}()
+
+	<-time.After(10 * time.Millisecond)
+
+	// The waiter is released and ctx is cancelled at the same time, the signal wins
+	ev.SetOne()
+	cancel()
+
+	assert.Nil(<-result)
+	assert.Equal(0, len(ev.waiters))
+}
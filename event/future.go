@@ -0,0 +1,222 @@
+package event
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+var _ Promise[int] = &promiseImpl[int]{}
+var _ Future[int] = &futureImpl[int]{}
+
+// NewPromise creates a new incomplete promise
+func NewPromise[T any]() Promise[T] {
+	return newPromise[T]()
+}
+
+// Resolved creates a future already completed with v
+func Resolved[T any](v T) Future[T] {
+	p := newPromise[T]()
+	p.Resolve(v)
+	return p.Future()
+}
+
+// Rejected creates a future already completed with err
+func Rejected[T any](err error) Future[T] {
+	p := newPromise[T]()
+	p.Reject(err)
+	return p.Future()
+}
+
+// Then creates a future completed with the result of fn applied to the value of f. Errors of f are passed along
+// without calling fn. fn runs in the goroutine that completes f, or right away if f is already complete
+func Then[T, R any](f Future[T], fn func(v T) (R, error)) Future[R] {
+	p := newPromise[R]()
+
+	onComplete(f, func() {
+		v, _, err := f.TryGet()
+		if err != nil {
+			p.Reject(err)
+			return
+		}
+
+		r, err := fn(v)
+		if err != nil {
+			p.Reject(err)
+			return
+		}
+
+		p.Resolve(r)
+	})
+
+	return p.Future()
+}
+
+// Map same as Then for functions that can't fail
+func Map[T, R any](f Future[T], fn func(v T) R) Future[R] {
+	return Then(f, func(v T) (R, error) {
+		return fn(v), nil
+	})
+}
+
+// onComplete calls fn once f is complete
+func onComplete[T any](f Future[T], fn func()) {
+	switch f := f.(type) {
+	case *promiseImpl[T]:
+		f.onComplete(fn)
+		return
+	case *futureImpl[T]:
+		f.p.onComplete(fn)
+		return
+	}
+
+	go func() {
+		<-f.Done()
+		fn()
+	}()
+}
+
+type promiseImpl[T any] struct {
+	mutex *sync.Mutex
+	ev    *eventImpl
+
+	complete bool
+	value    T
+	err      error
+
+	// callbacks called once on completion
+	callbacks []func()
+}
+
+func newPromise[T any]() *promiseImpl[T] {
+	return &promiseImpl[T]{
+		mutex: &sync.Mutex{},
+		ev:    NewEvent(false).(*eventImpl),
+	}
+}
+
+func (p *promiseImpl[T]) Resolve(v T) bool {
+	return p.settle(v, nil)
+}
+
+func (p *promiseImpl[T]) Reject(err error) bool {
+	if err == nil {
+		err = ErrNilRejection
+	}
+
+	var zero T
+	return p.settle(zero, err)
+}
+
+func (p *promiseImpl[T]) settle(v T, err error) bool {
+	p.mutex.Lock()
+
+	if p.complete {
+		p.mutex.Unlock()
+		return false
+	}
+
+	p.complete = true
+	p.value = v
+	p.err = err
+
+	callbacks := p.callbacks
+	p.callbacks = nil
+
+	p.mutex.Unlock()
+
+	p.ev.Set()
+
+	for _, fn := range callbacks {
+		fn()
+	}
+
+	return true
+}
+
+func (p *promiseImpl[T]) onComplete(fn func()) {
+	p.mutex.Lock()
+
+	if !p.complete {
+		p.callbacks = append(p.callbacks, fn)
+		p.mutex.Unlock()
+		return
+	}
+
+	p.mutex.Unlock()
+	fn()
+}
+
+func (p *promiseImpl[T]) Future() Future[T] {
+	return &futureImpl[T]{p: p}
+}
+
+func (p *promiseImpl[T]) Get(ctx context.Context) (T, error) {
+	if err := p.ev.WaitContext(ctx); err != nil {
+		var zero T
+		return zero, err
+	}
+
+	v, _, err := p.TryGet()
+	return v, err
+}
+
+func (p *promiseImpl[T]) TryGet() (T, bool, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.value, p.complete, p.err
+}
+
+func (p *promiseImpl[T]) Wait() {
+	p.ev.Wait()
+}
+
+func (p *promiseImpl[T]) WaitTimeout(d time.Duration) bool {
+	return p.ev.WaitTimeout(d)
+}
+
+func (p *promiseImpl[T]) WaitContext(ctx context.Context) error {
+	return p.ev.WaitContext(ctx)
+}
+
+func (p *promiseImpl[T]) Done() <-chan struct{} {
+	return p.ev.Done()
+}
+
+func (p *promiseImpl[T]) observe(fn func(change EventChange)) func() {
+	return p.ev.observe(fn)
+}
+
+// futureImpl read only side of a promise, it can't be converted back to a Promise
+type futureImpl[T any] struct {
+	p *promiseImpl[T]
+}
+
+func (f *futureImpl[T]) Get(ctx context.Context) (T, error) {
+	return f.p.Get(ctx)
+}
+
+func (f *futureImpl[T]) TryGet() (T, bool, error) {
+	return f.p.TryGet()
+}
+
+func (f *futureImpl[T]) Wait() {
+	f.p.Wait()
+}
+
+func (f *futureImpl[T]) WaitTimeout(d time.Duration) bool {
+	return f.p.WaitTimeout(d)
+}
+
+func (f *futureImpl[T]) WaitContext(ctx context.Context) error {
+	return f.p.WaitContext(ctx)
+}
+
+func (f *futureImpl[T]) Done() <-chan struct{} {
+	return f.p.Done()
+}
+
+func (f *futureImpl[T]) observe(fn func(change EventChange)) func() {
+	return f.p.observe(fn)
+}
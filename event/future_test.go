@@ -0,0 +1,150 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPromiseResolve(t *testing.T) {
+	assert := assert.New(t)
+	p := NewPromise[int]()
+
+	_, ok, _ := p.TryGet()
+	assert.False(ok)
+
+	go func() {
+		<-time.After(10 * time.Millisecond)
+		assert.True(p.Resolve(42))
+	}()
+
+	v, err := p.Future().Get(context.Background())
+	assert.Nil(err)
+	assert.Equal(42, v)
+
+	// Only the first completion counts
+	assert.False(p.Resolve(1))
+	assert.False(p.Reject(errors.New("late")))
+
+	v, ok, err = p.TryGet()
+	assert.True(ok)
+	assert.Nil(err)
+	assert.Equal(42, v)
+	<-p.Done()
+}
+
+func TestPromiseReject(t *testing.T) {
+	assert := assert.New(t)
+	p := NewPromise[string]()
+	expected := errors.New("failed")
+
+	assert.True(p.Reject(expected))
+
+	v, err := p.Get(context.Background())
+	assert.Equal(expected, err)
+	assert.Equal("", v)
+}
+
+func TestPromiseRejectNil(t *testing.T) {
+	assert := assert.New(t)
+	p := NewPromise[int]()
+
+	assert.True(p.Reject(nil))
+
+	_, ok, err := p.TryGet()
+	assert.True(ok)
+	assert.Equal(ErrNilRejection, err)
+}
+
+func TestFutureReadOnly(t *testing.T) {
+	assert := assert.New(t)
+	p := NewPromise[int]()
+
+	futures := []Future[int]{p.Future(), Resolved(1), Rejected[int](errors.New("failed")), Map(p.Future(), func(v int) int {
+		return v
+	})}
+
+	for _, f := range futures {
+		_, ok := f.(Promise[int])
+		assert.False(ok)
+	}
+
+	// The read only side still sees the completion
+	p.Resolve(2)
+	v, err := futures[3].Get(context.Background())
+	assert.Nil(err)
+	assert.Equal(2, v)
+}
+
+func TestFutureGetContext(t *testing.T) {
+	assert := assert.New(t)
+	p := NewPromise[int]()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := p.Get(ctx)
+	assert.Equal(context.DeadlineExceeded, err)
+	assert.False(p.WaitTimeout(0))
+}
+
+func TestFutureThen(t *testing.T) {
+	assert := assert.New(t)
+	p := NewPromise[int]()
+
+	str := Map(p.Future(), strconv.Itoa)
+	parsed := Then(str, func(s string) (int, error) {
+		return strconv.Atoi(s + "0")
+	})
+
+	assert.False(parsed.WaitTimeout(0))
+
+	p.Resolve(4)
+
+	v, err := parsed.Get(context.Background())
+	assert.Nil(err)
+	assert.Equal(40, v)
+
+	// Chaining a complete future runs right away
+	s, ok, _ := Map(Resolved(1), strconv.Itoa).TryGet()
+	assert.True(ok)
+	assert.Equal("1", s)
+}
+
+func TestFutureThenErrors(t *testing.T) {
+	assert := assert.New(t)
+	expected := errors.New("failed")
+
+	called := false
+	f := Map(Rejected[int](expected), func(v int) int {
+		called = true
+		return v
+	})
+
+	_, err := f.Get(context.Background())
+	assert.Equal(expected, err)
+	assert.False(called)
+
+	f = Then(Resolved(1), func(v int) (int, error) {
+		return 0, expected
+	})
+
+	_, err = f.Get(context.Background())
+	assert.Equal(expected, err)
+}
+
+func TestFutureWaitAny(t *testing.T) {
+	assert := assert.New(t)
+	a := NewPromise[int]()
+	b := NewPromise[string]()
+
+	b.Resolve("b")
+
+	index, err := WaitAny(context.Background(), a, b)
+	assert.Nil(err)
+	assert.Equal(1, index)
+}
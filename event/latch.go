@@ -0,0 +1,68 @@
+package event
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+var _ CountDownLatch = &countDownLatchImpl{}
+
+// NewCountDownLatch creates a new latch released after n calls to CountDown
+func NewCountDownLatch(n int) CountDownLatch {
+	if n < 0 {
+		n = 0
+	}
+
+	return &countDownLatchImpl{
+		mutex: &sync.Mutex{},
+		count: n,
+		ev:    NewEvent(n == 0).(*eventImpl),
+	}
+}
+
+type countDownLatchImpl struct {
+	mutex *sync.Mutex
+	count int
+	ev    *eventImpl
+}
+
+func (l *countDownLatchImpl) CountDown() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.count == 0 {
+		return
+	}
+
+	l.count--
+	if l.count == 0 {
+		l.ev.Set()
+	}
+}
+
+func (l *countDownLatchImpl) Count() int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.count
+}
+
+func (l *countDownLatchImpl) Wait() {
+	l.ev.Wait()
+}
+
+func (l *countDownLatchImpl) WaitTimeout(d time.Duration) bool {
+	return l.ev.WaitTimeout(d)
+}
+
+func (l *countDownLatchImpl) WaitContext(ctx context.Context) error {
+	return l.ev.WaitContext(ctx)
+}
+
+func (l *countDownLatchImpl) Done() <-chan struct{} {
+	return l.ev.Done()
+}
+
+func (l *countDownLatchImpl) observe(fn func(change EventChange)) func() {
+	return l.ev.observe(fn)
+}
@@ -0,0 +1,51 @@
+package event
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatchCountDown(t *testing.T) {
+	assert := assert.New(t)
+	l := NewCountDownLatch(3)
+
+	assert.Equal(3, l.Count())
+	assert.False(l.WaitTimeout(10 * time.Millisecond))
+
+	for i := 0; i < 3; i++ {
+		go func() {
+			<-time.After(10 * time.Millisecond)
+			l.CountDown()
+		}()
+	}
+
+	assert.Nil(l.WaitContext(context.Background()))
+	assert.Equal(0, l.Count())
+	<-l.Done()
+
+	// Extra calls are ignored
+	l.CountDown()
+	assert.Equal(0, l.Count())
+	l.Wait()
+}
+
+func TestLatchContext(t *testing.T) {
+	assert := assert.New(t)
+	l := NewCountDownLatch(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.Equal(context.DeadlineExceeded, l.WaitContext(ctx))
+	assert.Equal(1, l.Count())
+}
+
+func TestLatchZero(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(NewCountDownLatch(0).WaitTimeout(0))
+	assert.True(NewCountDownLatch(-1).WaitTimeout(0))
+}
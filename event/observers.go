@@ -0,0 +1,49 @@
+package event
+
+import "sync"
+
+// observable is implemented by the events of this package, derived events use it to follow their inputs
+type observable interface {
+	// observe registers fn to be called after every state change. Returns a function to remove it
+	observe(fn func(change EventChange)) func()
+}
+
+// observers list of callbacks notified on state changes. The zero value is ready to use
+type observers struct {
+	mutex sync.Mutex
+	fns   map[uint64]func(change EventChange)
+	next  uint64
+}
+
+func (o *observers) add(fn func(change EventChange)) func() {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if o.fns == nil {
+		o.fns = map[uint64]func(change EventChange){}
+	}
+
+	id := o.next
+	o.next++
+	o.fns[id] = fn
+
+	return func() {
+		o.mutex.Lock()
+		defer o.mutex.Unlock()
+		delete(o.fns, id)
+	}
+}
+
+// notify calls every observer. Must be called without holding the event lock, observers read the event state
+func (o *observers) notify(change EventChange) {
+	o.mutex.Lock()
+	fns := make([]func(change EventChange), 0, len(o.fns))
+	for _, fn := range o.fns {
+		fns = append(fns, fn)
+	}
+	o.mutex.Unlock()
+
+	for _, fn := range fns {
+		fn(change)
+	}
+}
@@ -0,0 +1,26 @@
+package event
+
+// Option configures an event created with NewEvent
+type Option func(s *eventImpl)
+
+// WithName names the event and adds it to the registry, see Lookup and Dump. A previous event with the same name
+// is replaced. Call Unregister once the event is no longer used
+func WithName(name string) Option {
+	return func(s *eventImpl) {
+		s.name = name
+	}
+}
+
+// WithStats records the number of calls to Set and Reset, and the time and caller of the last ones
+func WithStats() Option {
+	return func(s *eventImpl) {
+		s.stats = true
+	}
+}
+
+// WithListener registers fn to be called after every state transition
+func WithListener(fn func(change EventChange)) Option {
+	return func(s *eventImpl) {
+		s.observers.add(fn)
+	}
+}
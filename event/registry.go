@@ -0,0 +1,97 @@
+package event
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+var registry = struct {
+	mutex  sync.Mutex
+	events map[string]InstrumentedEvent
+}{
+	events: map[string]InstrumentedEvent{},
+}
+
+func register(name string, ev InstrumentedEvent) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	registry.events[name] = ev
+}
+
+// Unregister removes a named event from the registry
+func Unregister(name string) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	delete(registry.events, name)
+}
+
+// Lookup returns the event registered with "name"
+func Lookup(name string) (InstrumentedEvent, bool) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	ev, ok := registry.events[name]
+	return ev, ok
+}
+
+// Snapshot returns the stats of all the named events sorted by name
+func Snapshot() []EventStats {
+	registry.mutex.Lock()
+	events := make([]InstrumentedEvent, 0, len(registry.events))
+	for _, ev := range registry.events {
+		events = append(events, ev)
+	}
+	registry.mutex.Unlock()
+
+	stats := make([]EventStats, 0, len(events))
+	for _, ev := range events {
+		stats = append(stats, ev.Stats())
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Name < stats[j].Name
+	})
+
+	return stats
+}
+
+// Dump writes the stats of all the named events to w, one event per line
+func Dump(w io.Writer) error {
+	for _, st := range Snapshot() {
+		_, err := fmt.Fprintf(w, "%s: set=%t waiters=%d sets=%d resets=%d last_set=%s by %s last_reset=%s by %s\n",
+			st.Name, st.IsSet, st.Waiters, st.Sets, st.Resets,
+			formatTime(st.LastSet), orNone(st.LastSetBy), formatTime(st.LastReset), orNone(st.LastResetBy))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// callerName returns the caller "skip" frames up as file:line
+func callerName(skip int) string {
+	_, file, line, ok := runtime.Caller(skip)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", file, line)
+}
@@ -0,0 +1,123 @@
+package event
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventStats(t *testing.T) {
+	assert := assert.New(t)
+	ev := NewInstrumentedEvent(false)
+
+	go ev.Wait()
+	go ev.Wait()
+
+	deadline := time.Now().Add(time.Second)
+	for ev.Stats().Waiters != 2 && time.Now().Before(deadline) {
+		<-time.After(time.Millisecond)
+	}
+	assert.Equal(2, ev.Stats().Waiters)
+
+	ev.Set()
+	ev.Set()
+	ev.Reset()
+
+	stats := ev.Stats()
+	assert.False(stats.IsSet)
+	assert.Equal(0, stats.Waiters)
+	assert.Equal(uint64(2), stats.Sets)
+	assert.Equal(uint64(1), stats.Resets)
+	assert.False(stats.LastSet.IsZero())
+	assert.False(stats.LastReset.IsZero())
+	assert.Contains(stats.LastSetBy, "registry_test.go:")
+	assert.Contains(stats.LastResetBy, "registry_test.go:")
+}
+
+func TestEventStatsDisabled(t *testing.T) {
+	assert := assert.New(t)
+	ev := NewEvent(false).(InstrumentedEvent)
+
+	ev.Set()
+
+	stats := ev.Stats()
+	assert.True(stats.IsSet)
+	assert.Equal(uint64(0), stats.Sets)
+	assert.Equal("", stats.LastSetBy)
+}
+
+func TestEventListener(t *testing.T) {
+	assert := assert.New(t)
+
+	changes := []EventChange{}
+	ev := NewInstrumentedEvent(false, WithName("listener"), WithListener(func(change EventChange) {
+		changes = append(changes, change)
+	}))
+	defer Unregister("listener")
+
+	late := 0
+	remove := ev.OnChange(func(change EventChange) {
+		late++
+	})
+
+	ev.Set()
+	// No transition, no callback
+	ev.SetOne()
+	ev.Reset()
+
+	remove()
+	ev.Set()
+
+	assert.Equal(3, len(changes))
+	assert.Equal(2, late)
+
+	assert.Equal("listener", changes[0].Name)
+	assert.True(changes[0].IsSet)
+	assert.Contains(changes[0].Caller, "registry_test.go:")
+	assert.False(changes[1].IsSet)
+	assert.True(changes[2].IsSet)
+}
+
+func TestRegistry(t *testing.T) {
+	assert := assert.New(t)
+
+	ready := NewEvent(false, WithName("test.ready"), WithStats())
+	NewEvent(true, WithName("test.another"))
+	defer Unregister("test.ready")
+	defer Unregister("test.another")
+
+	found, ok := Lookup("test.ready")
+	assert.True(ok)
+	assert.Equal(ready, found)
+
+	ready.Set()
+
+	names := []string{}
+	for _, st := range Snapshot() {
+		names = append(names, st.Name)
+	}
+	assert.Subset(names, []string{"test.another", "test.ready"})
+
+	out := &bytes.Buffer{}
+	assert.Nil(Dump(out))
+
+	lines := strings.Split(out.String(), "\n")
+	var readyLine string
+	for _, line := range lines {
+		if strings.HasPrefix(line, "test.ready:") {
+			readyLine = line
+		}
+	}
+
+	assert.Contains(readyLine, "set=true")
+	assert.Contains(readyLine, "sets=1")
+	assert.Contains(readyLine, "registry_test.go:")
+	assert.Contains(readyLine, "last_reset=never by -")
+
+	Unregister("test.ready")
+	_, ok = Lookup("test.ready")
+	assert.False(ok)
+}
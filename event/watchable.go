@@ -0,0 +1,88 @@
+package event
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+var _ Watchable[int] = &watchableImpl[int]{}
+
+// NewWatchable creates a new watchable value at version zero
+func NewWatchable[T any](initValue T) Watchable[T] {
+	w := &watchableImpl[T]{
+		mutex: &sync.Mutex{},
+	}
+
+	w.state.Store(&watchableState[T]{
+		value:   initValue,
+		changed: NewEvent(false).(*eventImpl),
+	})
+
+	return w
+}
+
+// watchableState is one immutable version of the value. changed is set when a newer version replaces it
+type watchableState[T any] struct {
+	value   T
+	version uint64
+	changed *eventImpl
+}
+
+type watchableImpl[T any] struct {
+	// mutex serializes writers, readers only load state
+	mutex *sync.Mutex
+	state atomic.Pointer[watchableState[T]]
+}
+
+func (w *watchableImpl[T]) Load() (T, uint64) {
+	st := w.state.Load()
+	return st.value, st.version
+}
+
+// replace stores a new version after old. Must be called with the lock held
+func (w *watchableImpl[T]) replace(old *watchableState[T], v T) uint64 {
+	st := &watchableState[T]{
+		value:   v,
+		version: old.version + 1,
+		changed: NewEvent(false).(*eventImpl),
+	}
+
+	w.state.Store(st)
+	old.changed.Set()
+
+	return st.version
+}
+
+func (w *watchableImpl[T]) Store(v T) uint64 {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.replace(w.state.Load(), v)
+}
+
+func (w *watchableImpl[T]) CompareAndSwap(version uint64, v T) (uint64, bool) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	old := w.state.Load()
+	if old.version != version {
+		return old.version, false
+	}
+
+	return w.replace(old, v), true
+}
+
+func (w *watchableImpl[T]) Changed(ctx context.Context, sinceVersion uint64) (T, uint64, error) {
+	for {
+		st := w.state.Load()
+		if st.version > sinceVersion {
+			return st.value, st.version, nil
+		}
+
+		if err := st.changed.WaitContext(ctx); err != nil {
+			v, version := w.Load()
+			return v, version, err
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package event
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchableLoadStore(t *testing.T) {
+	assert := assert.New(t)
+	w := NewWatchable("a")
+
+	v, version := w.Load()
+	assert.Equal("a", v)
+	assert.Equal(uint64(0), version)
+
+	assert.Equal(uint64(1), w.Store("b"))
+
+	v, version = w.Load()
+	assert.Equal("b", v)
+	assert.Equal(uint64(1), version)
+}
+
+func TestWatchableCompareAndSwap(t *testing.T) {
+	assert := assert.New(t)
+	w := NewWatchable(1)
+
+	version, ok := w.CompareAndSwap(0, 2)
+	assert.True(ok)
+	assert.Equal(uint64(1), version)
+
+	version, ok = w.CompareAndSwap(0, 3)
+	assert.False(ok)
+	assert.Equal(uint64(1), version)
+
+	v, _ := w.Load()
+	assert.Equal(2, v)
+}
+
+func TestWatchableChanged(t *testing.T) {
+	assert := assert.New(t)
+	w := NewWatchable(0)
+
+	go func() {
+		<-time.After(10 * time.Millisecond)
+		w.Store(1)
+	}()
+
+	v, version, err := w.Changed(context.Background(), 0)
+	assert.Nil(err)
+	assert.Equal(1, v)
+	assert.Equal(uint64(1), version)
+
+	// Already newer, doesn't block
+	v, _, err = w.Changed(context.Background(), 0)
+	assert.Nil(err)
+	assert.Equal(1, v)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	v, version, err = w.Changed(ctx, 1)
+	assert.Equal(context.DeadlineExceeded, err)
+	assert.Equal(1, v)
+	assert.Equal(uint64(1), version)
+}
+
+func TestWatchableBroadcast(t *testing.T) {
+	assert := assert.New(t)
+	w := NewWatchable(0)
+
+	const watchers = 16
+	const updates = 100
+
+	var wg sync.WaitGroup
+	for i := 0; i < watchers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			// Every watcher sees versions in increasing order and ends up at the last one
+			var since uint64
+			for since < updates {
+				v, version, err := w.Changed(context.Background(), since)
+				if !assert.Nil(err) {
+					return
+				}
+				assert.Greater(version, since)
+				assert.Equal(int(version), v)
+				since = version
+			}
+		}()
+	}
+
+	for i := 1; i <= updates; i++ {
+		w.Store(i)
+	}
+
+	wg.Wait()
+}
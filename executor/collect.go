@@ -0,0 +1,212 @@
+package executor
+
+import (
+	"context"
+	"sync"
+
+	"github.com/GustavoKatel/asyncutils/executor/interfaces"
+)
+
+// CollectMode decides what Collect does when a job fails
+type CollectMode = interfaces.CollectMode
+
+const (
+	// CollectAll see interfaces.CollectAll
+	CollectAll = interfaces.CollectAll
+
+	// CollectFailFast see interfaces.CollectFailFast
+	CollectFailFast = interfaces.CollectFailFast
+)
+
+// collector gathers the results of the jobs of a Collect call. Every job is reported exactly once: when it
+// finishes, when it can't be posted or is dropped, or with ErrExecutorStopped if the executor stops first
+// (ErrJobCancelled after ShutdownNow)
+type collector struct {
+	ge      *goExecutor
+	mode    CollectMode
+	ordered bool
+
+	// ctx is passed to the jobs, it is cancelled when the collect is over
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	jobs []*jobImpl
+
+	mutex     *sync.Mutex
+	results   []*interfaces.JobResultIndexed
+	remaining int
+
+	// next index to publish when ordered
+	next   int
+	closed bool
+
+	// ch has room for every result, so publishing never blocks
+	ch chan *interfaces.JobResultIndexed
+}
+
+func (ge *goExecutor) collect(ordered bool, jobs []interfaces.JobWithResultFn) *collector {
+	ctx, cancel := context.WithCancel(ge.ctx)
+
+	c := &collector{
+		ge:        ge,
+		mode:      ge.collectMode,
+		ordered:   ordered,
+		ctx:       ctx,
+		cancel:    cancel,
+		jobs:      make([]*jobImpl, len(jobs)),
+		mutex:     &sync.Mutex{},
+		results:   make([]*interfaces.JobResultIndexed, len(jobs)),
+		remaining: len(jobs),
+		ch:        make(chan *interfaces.JobResultIndexed, len(jobs)),
+	}
+
+	if len(jobs) == 0 {
+		close(c.ch)
+		cancel()
+		return c
+	}
+
+	for i, job := range jobs {
+		pos := i
+		jobFn := job
+
+		jobSpec := newJob(func(context.Context) error {
+			// The collect is already over
+			if c.ctx.Err() != nil {
+				return nil
+			}
+
+			r, err := ge.callJobWithResult(c.ctx, jobFn)
+			c.report(pos, r, err)
+
+			return err
+		})
+		jobSpec.index = pos
+		jobSpec.onDrop = func(err error) {
+			c.report(pos, nil, err)
+		}
+
+		c.jobs[pos] = jobSpec
+	}
+
+	go c.watch()
+
+	for _, jobSpec := range c.jobs {
+		if err := ge.postJob(jobSpec); err != nil {
+			ge.emitError(newJobError(jobSpec, err))
+			c.report(jobSpec.index, nil, err)
+		}
+	}
+
+	return c
+}
+
+// report records the outcome of the job at pos
+func (c *collector) report(pos int, r interface{}, err error) {
+	c.mutex.Lock()
+	failed := c.reportLocked(pos, r, err)
+	closed := c.closed
+	c.mutex.Unlock()
+
+	if failed {
+		c.cancelPending()
+	}
+
+	if closed {
+		c.cancel()
+	}
+}
+
+// reportLocked publishes the result and closes the channel when the collect is over.
+// Returns true if the collect failed fast. Must be called with the lock held
+func (c *collector) reportLocked(pos int, r interface{}, err error) bool {
+	if c.closed || c.results[pos] != nil {
+		return false
+	}
+
+	res := &interfaces.JobResultIndexed{
+		Index:  pos,
+		Result: r,
+		Err:    err,
+	}
+	c.results[pos] = res
+	c.remaining--
+
+	failed := err != nil && c.mode == CollectFailFast
+
+	if c.ordered {
+		for ; c.next < len(c.results) && c.results[c.next] != nil; c.next++ {
+			c.ch <- c.results[c.next]
+		}
+
+		// The failing result ends the channel even if the ones before it are missing
+		if failed && pos >= c.next {
+			c.ch <- res
+		}
+	} else {
+		c.ch <- res
+	}
+
+	if failed || c.remaining == 0 {
+		c.closed = true
+		close(c.ch)
+	}
+
+	return failed
+}
+
+// cancelPending removes the jobs that didn't start, the running ones see their context cancelled
+func (c *collector) cancelPending() {
+	for _, job := range c.jobs {
+		c.ge.removeJob(job)
+	}
+}
+
+// watch reports the jobs that didn't finish once the executor stops
+func (c *collector) watch() {
+	<-c.ctx.Done()
+
+	err := c.ge.abandonedErr()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for pos := range c.results {
+		if c.closed {
+			return
+		}
+
+		if c.results[pos] == nil {
+			c.reportLocked(pos, nil, err)
+		}
+	}
+}
+
+func (ge *goExecutor) CollectChan(jobs ...interfaces.JobWithResultFn) <-chan *interfaces.JobResultIndexed {
+	return ge.collect(true, jobs).ch
+}
+
+func (ge *goExecutor) CollectChanFirstServe(jobs ...interfaces.JobWithResultFn) <-chan *interfaces.JobResultIndexed {
+	return ge.collect(false, jobs).ch
+}
+
+func (ge *goExecutor) Collect(jobs ...interfaces.JobWithResultFn) ([]interface{}, error) {
+	c := ge.collect(true, jobs)
+	results := make([]interface{}, len(jobs))
+	errs := []error{}
+
+	for r := range c.ch {
+		if r.Err != nil {
+			errs = append(errs, newJobError(c.jobs[r.Index], r.Err))
+			continue
+		}
+
+		results[r.Index] = r.Result
+	}
+
+	if len(errs) > 0 {
+		return results, &CollectError{Errors: errs}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,163 @@
+package executor
+
+import (
+	"sync"
+
+	"github.com/GustavoKatel/asyncutils/executor/interfaces"
+	"github.com/GustavoKatel/asyncutils/queue"
+)
+
+// DefaultErrorBuffer default number of undelivered errors kept per channel with ErrorDeliveryBuffered
+const DefaultErrorBuffer = 1024
+
+// ErrorDelivery decides how errors are delivered to a channel registered with SubscribeErrors
+type ErrorDelivery = interfaces.ErrorDelivery
+
+const (
+	// ErrorDeliveryBuffered see interfaces.ErrorDeliveryBuffered
+	ErrorDeliveryBuffered = interfaces.ErrorDeliveryBuffered
+
+	// ErrorDeliveryBlock see interfaces.ErrorDeliveryBlock
+	ErrorDeliveryBlock = interfaces.ErrorDeliveryBlock
+
+	// ErrorDeliveryDrop see interfaces.ErrorDeliveryDrop
+	ErrorDeliveryDrop = interfaces.ErrorDeliveryDrop
+)
+
+type errorSubscriber struct {
+	ch       chan error
+	delivery ErrorDelivery
+
+	// buffer pending errors with ErrorDeliveryBuffered
+	buffer queue.BlockingQueue[error]
+
+	// done is closed when the channel is unregistered
+	done chan struct{}
+
+	// forwarding is true while a forwardErrors goroutine is delivering the buffer
+	mutex      *sync.Mutex
+	forwarding bool
+}
+
+func (ge *goExecutor) ErrorChan(ch chan error) {
+	ge.SubscribeErrors(ch, ErrorDeliveryBuffered)
+}
+
+func (ge *goExecutor) SubscribeErrors(ch chan error, delivery ErrorDelivery) func() {
+	sub := &errorSubscriber{
+		ch:       ch,
+		delivery: delivery,
+		done:     make(chan struct{}),
+		mutex:    &sync.Mutex{},
+	}
+
+	if delivery == ErrorDeliveryBuffered {
+		sub.buffer = queue.NewBoundedWithPolicy[error](ge.errorBuffer, queue.OverflowDropOldest, nil)
+	}
+
+	ge.errorChsMutex.Lock()
+	ge.errorSubs = append(ge.errorSubs, sub)
+	ge.errorChsMutex.Unlock()
+
+	once := &sync.Once{}
+	return func() {
+		once.Do(func() {
+			ge.unsubscribeErrors(sub)
+		})
+	}
+}
+
+func (ge *goExecutor) unsubscribeErrors(sub *errorSubscriber) {
+	// Closed first to release a worker blocked sending to this channel
+	close(sub.done)
+
+	ge.errorChsMutex.Lock()
+	defer ge.errorChsMutex.Unlock()
+
+	for i, other := range ge.errorSubs {
+		if other == sub {
+			ge.errorSubs = append(ge.errorSubs[:i:i], ge.errorSubs[i+1:]...)
+			return
+		}
+	}
+}
+
+// forwardErrors sends the buffered errors of sub in order, it returns once the buffer is empty or sub is unregistered.
+// Started on demand, so no goroutine is left behind by a subscriber that has nothing to deliver
+func (ge *goExecutor) forwardErrors(sub *errorSubscriber) {
+	for {
+		sub.mutex.Lock()
+		err, ok := sub.buffer.TryPopFront()
+		if !ok {
+			sub.forwarding = false
+			sub.mutex.Unlock()
+			return
+		}
+		sub.mutex.Unlock()
+
+		select {
+		case sub.ch <- err:
+		case <-sub.done:
+			sub.mutex.Lock()
+			sub.forwarding = false
+			sub.mutex.Unlock()
+			return
+		}
+	}
+}
+
+// bufferError queues err for sub and starts forwarding it if nothing is
+func (ge *goExecutor) bufferError(sub *errorSubscriber, err error) {
+	select {
+	case <-sub.done:
+		return
+	default:
+	}
+
+	sub.mutex.Lock()
+	defer sub.mutex.Unlock()
+
+	sub.buffer.PushBack(err)
+
+	if !sub.forwarding {
+		sub.forwarding = true
+		go ge.forwardErrors(sub)
+	}
+}
+
+// emitError delivers the error of a job to every subscriber
+func (ge *goExecutor) emitError(jobErr *JobError) {
+	ge.errorChsMutex.RLock()
+	subs := make([]*errorSubscriber, len(ge.errorSubs))
+	copy(subs, ge.errorSubs)
+	ge.errorChsMutex.RUnlock()
+
+	for _, sub := range subs {
+		switch sub.delivery {
+		case ErrorDeliveryBuffered:
+			ge.bufferError(sub, jobErr)
+
+		case ErrorDeliveryBlock:
+			select {
+			case sub.ch <- jobErr:
+			case <-sub.done:
+			}
+
+		case ErrorDeliveryDrop:
+			select {
+			case sub.ch <- jobErr:
+			default:
+			}
+		}
+	}
+}
+
+// newJobError creates the error reported for a job
+func newJobError(job *jobImpl, err error) *JobError {
+	return &JobError{
+		JobID:   job.id,
+		Index:   job.index,
+		Err:     err,
+		Attempt: 1,
+	}
+}
@@ -1,8 +1,121 @@
 package executor
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/GustavoKatel/asyncutils/queue"
+)
 
 var (
 	// ErrExecutorStopped tried to enqueue a job with the executor stopped
 	ErrExecutorStopped = errors.New("Executor is stopped")
+
+	// ErrQueueFull tried to enqueue a job with the queue full and the queue.OverflowReject policy
+	ErrQueueFull = queue.ErrQueueFull
+
+	// ErrJobDropped a pending job was dropped by the queue overflow policy
+	ErrJobDropped = errors.New("Job dropped by the queue overflow policy")
+
+	// ErrJobCancelled the job was cancelled before starting
+	ErrJobCancelled = errors.New("Job cancelled")
+
+	// ErrInvalidCategoryLimit WithCategoryLimit was used with a limit lower than 1
+	ErrInvalidCategoryLimit = errors.New("Category limit must be at least 1")
 )
+
+// JobError error of a job sent to the channels registered with ErrorChan and SubscribeErrors
+type JobError struct {
+	// JobID id of the job in its executor
+	JobID uint64
+
+	// Index position of the job in the Collect call that posted it, -1 for other jobs
+	Index int
+
+	// Err error returned by the job, or the reason it didn't run
+	Err error
+
+	// Attempt number of the execution that failed, starting at 1
+	Attempt int
+
+	// Started and Finished bound the execution of the job, zero if it didn't run
+	Started  time.Time
+	Finished time.Time
+}
+
+func (e *JobError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *JobError) Unwrap() error {
+	return e.Err
+}
+
+// PanicError a job panicked, reported as its error unless WithRepanic is used
+type PanicError struct {
+	// Value value passed to panic
+	Value interface{}
+
+	// Stack stack trace of the goroutine when the job panicked
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("Job panicked: %v", e.Value)
+}
+
+// Unwrap returns Value if it is an error
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// CollectError errors of the jobs that failed in a Collect call, sorted by their index
+type CollectError struct {
+	// Errors one *JobError per failed job
+	Errors []error
+}
+
+func (e *CollectError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		var jobErr *JobError
+		if errors.As(err, &jobErr) {
+			msgs = append(msgs, fmt.Sprintf("job %d: %v", jobErr.Index, jobErr.Err))
+		} else {
+			msgs = append(msgs, err.Error())
+		}
+	}
+
+	return fmt.Sprintf("%d jobs failed: %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the errors of the jobs for errors.Is and errors.As in Go 1.20 and later
+func (e *CollectError) Unwrap() []error {
+	return e.Errors
+}
+
+// Is reports whether any of the errors of the jobs matches target, errors.Is only walks Unwrap() []error
+// since Go 1.20
+func (e *CollectError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As finds the first error of the jobs that matches target, see Is
+func (e *CollectError) As(target interface{}) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -1,5 +1,23 @@
 package executor
 
-import "github.com/GustavoKatel/asyncutils/executor/interfaces"
+import (
+	"context"
+
+	"github.com/GustavoKatel/asyncutils/event"
+	"github.com/GustavoKatel/asyncutils/executor/interfaces"
+)
 
 type Executor interfaces.Executor
+
+// SubmitTyped same as Executor.Submit for jobs returning a value of type T
+func SubmitTyped[T any](exec Executor, job func(ctx context.Context) (T, error)) event.Future[T] {
+	f := exec.Submit(func(ctx context.Context) (interface{}, error) {
+		return job(ctx)
+	})
+
+	return event.Map(f, func(v interface{}) T {
+		// v is nil if T is an interface type and the job returned nil
+		r, _ := v.(T)
+		return r
+	})
+}
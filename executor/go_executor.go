@@ -2,68 +2,211 @@ package executor
 
 import (
 	"context"
+	"runtime/debug"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/GustavoKatel/asyncutils/event"
 	"github.com/GustavoKatel/asyncutils/executor/interfaces"
 	"github.com/GustavoKatel/asyncutils/queue"
+	"github.com/GustavoKatel/asyncutils/semaphore"
 )
 
 var _ interfaces.Executor = &goExecutor{}
 
 type goExecutor struct {
-	queue      queue.Queue
+	queue      queue.InstrumentedQueue[*jobImpl]
 	queueMutex *sync.Mutex
 
-	hasJobsEvent event.Event
+	queueCapacity int
+	queuePolicy   queue.OverflowPolicy
 
 	workers int
 
-	errorChs      []chan error
+	// categories concurrency limits by job category
+	categories map[string]semaphore.Semaphore
+
+	errorSubs     []*errorSubscriber
 	errorChsMutex *sync.RWMutex
+	errorBuffer   int
+
+	collectMode CollectMode
+	repanic     bool
+
+	// optionErr invalid configuration found by an option
+	optionErr error
 
 	ctx       context.Context
 	ctxCancel context.CancelFunc
+
+	// workersWg tracks the running workers
+	workersWg *sync.WaitGroup
+
+	// stateMutex protects the fields below
+	stateMutex *sync.Mutex
+
+	// closing is set by Shutdown and ShutdownNow, no more jobs are accepted
+	closing bool
+
+	// shutdownNow is set by ShutdownNow, the jobs that didn't start are reported with ErrJobCancelled
+	shutdownNow bool
+
+	// pending jobs accepted and not finished yet, including the ones waiting for a category permit
+	pending int
+
+	// drained is set once closing and there are no pending jobs
+	drained event.Event
+
+	// parked jobs waiting for a category permit
+	parked map[*jobImpl]struct{}
+
+	// lastJobID id of the last job posted
+	lastJobID uint64
 }
 
-// NewDefaultExecutor creates a new default executor which maps workers as gorountines
-func NewDefaultExecutor(workers int) (interfaces.Executor, error) {
-	return NewDefaultExecutorContext(context.Background(), workers)
+// NewDefaultExecutor creates a new default executor which maps workers as gorountines.
+// The queue is unbounded unless WithQueueCapacity is set, PostJob never waits and the queue can grow without limit
+func NewDefaultExecutor(workers int, opts ...Option) (interfaces.Executor, error) {
+	return NewDefaultExecutorContext(context.Background(), workers, opts...)
 }
 
-// NewDefaultExecutorContext creates a new default executor which maps workers as gorountines
-func NewDefaultExecutorContext(ctx context.Context, workers int) (interfaces.Executor, error) {
+// NewDefaultExecutorContext creates a new default executor which maps workers as gorountines.
+// The queue is unbounded unless WithQueueCapacity is set, see NewDefaultExecutor
+func NewDefaultExecutorContext(ctx context.Context, workers int, opts ...Option) (interfaces.Executor, error) {
 	ctx, cancel := context.WithCancel(ctx)
 
 	exec := &goExecutor{
-		queue:      queue.New(),
 		queueMutex: &sync.Mutex{},
 
-		hasJobsEvent: event.NewEvent(false),
-
 		workers: workers,
 
-		errorChs:      []chan error{},
+		categories: map[string]semaphore.Semaphore{},
+
+		errorSubs:     []*errorSubscriber{},
 		errorChsMutex: &sync.RWMutex{},
+		errorBuffer:   DefaultErrorBuffer,
 
 		ctx:       ctx,
 		ctxCancel: cancel,
+
+		workersWg:  &sync.WaitGroup{},
+		stateMutex: &sync.Mutex{},
+		drained:    event.NewEvent(false),
+		parked:     map[*jobImpl]struct{}{},
+	}
+
+	for _, opt := range opts {
+		opt(exec)
 	}
 
+	if exec.optionErr != nil {
+		cancel()
+		return nil, exec.optionErr
+	}
+
+	exec.queue = queue.NewInstrumented(exec.queueCapacity, exec.queuePolicy, exec.dropJob)
+
 	return exec, nil
 }
 
 func (ge *goExecutor) Start() error {
+	// Added before starting so Shutdown can't miss the workers
+	ge.workersWg.Add(ge.workers)
 	go ge.background()
 	return nil
 }
 
 func (ge *goExecutor) Stop() error {
 	ge.ctxCancel()
-	ge.hasJobsEvent.Set()
 	return nil
 }
 
+// Shutdown stops accepting jobs and waits for the pending ones to finish, then stops the workers.
+// If ctx is done first it returns ctx.Err() and the pending jobs keep running, call Stop or ShutdownNow to abandon them
+func (ge *goExecutor) Shutdown(ctx context.Context) error {
+	ge.stateMutex.Lock()
+	ge.closing = true
+	if ge.pending == 0 {
+		ge.drained.Set()
+	}
+	ge.stateMutex.Unlock()
+
+	// After Stop the workers leave the queued jobs in the queue, only the running ones are waited
+	select {
+	case <-ge.drained.Done():
+	case <-ge.ctx.Done():
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	ge.ctxCancel()
+
+	workersDone := make(chan struct{})
+	go func() {
+		ge.workersWg.Wait()
+		close(workersDone)
+	}()
+
+	select {
+	case <-workersDone:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ShutdownNow stops accepting jobs and the executor, returning the jobs that didn't start
+func (ge *goExecutor) ShutdownNow() []interfaces.JobFn {
+	ge.stateMutex.Lock()
+	ge.closing = true
+	ge.shutdownNow = true
+	ge.stateMutex.Unlock()
+
+	// Cancelled before draining, the workers don't pop anything once ctx is done. A job parked after
+	// this point fails to get its permit and is reported with ErrExecutorStopped
+	ge.ctxCancel()
+
+	ge.stateMutex.Lock()
+	parked := make([]*jobImpl, 0, len(ge.parked))
+	for job := range ge.parked {
+		parked = append(parked, job)
+	}
+	ge.parked = map[*jobImpl]struct{}{}
+	ge.stateMutex.Unlock()
+
+	jobs := []interfaces.JobFn{}
+	for _, job := range append(ge.queue.DrainTo(0), parked...) {
+		if job.hasPermit {
+			ge.categories[job.category].Release(1)
+		}
+
+		if job.onDrop != nil {
+			job.onDrop(ErrJobCancelled)
+		}
+
+		if job.handle != nil {
+			job.handle.finish(interfaces.JobCancelled, ErrJobCancelled)
+		}
+
+		jobs = append(jobs, job.jobFn)
+		ge.jobDone()
+	}
+
+	return jobs
+}
+
+// jobDone is called once for every accepted job, after it runs or when it is dropped
+func (ge *goExecutor) jobDone() {
+	ge.stateMutex.Lock()
+	defer ge.stateMutex.Unlock()
+
+	ge.pending--
+	if ge.closing && ge.pending == 0 {
+		ge.drained.Set()
+	}
+}
+
 func (ge *goExecutor) background() {
 	for i := 0; i < ge.workers; i++ {
 		go ge.worker(i)
@@ -71,161 +214,306 @@ func (ge *goExecutor) background() {
 }
 
 func (ge *goExecutor) worker(id int) {
-	for ge.ctx.Err() == nil {
-		ge.hasJobsEvent.Wait()
+	defer ge.workersWg.Done()
 
-		jobI := ge.queue.PopFront()
+	for {
+		// Blocks until a job is available or the executor is stopped, the queued jobs are left in the queue
+		job, err := ge.queue.PopFrontCtx(ge.ctx)
+		if err != nil {
+			return
+		}
 
-		// The queue is empty
-		if jobI == nil {
-			ge.hasJobsEvent.Reset()
-			continue
+		ge.run(job)
+	}
+}
+
+func (ge *goExecutor) run(job *jobImpl) {
+	sem := ge.categories[job.category]
+	if sem != nil {
+		if !job.hasPermit && !sem.TryAcquire(1) {
+			ge.stateMutex.Lock()
+			ge.parked[job] = struct{}{}
+			ge.stateMutex.Unlock()
+
+			go ge.waitPermit(sem, job)
+			return
 		}
 
-		job := jobI.(*jobImpl)
+		defer sem.Release(1)
+	}
+
+	defer ge.jobDone()
 
-		if err := job.jobFn(ge.ctx); err != nil {
-			ge.emitError(err)
+	ctx := ge.ctx
+	if job.handle != nil {
+		// Cancelled while queued
+		if !job.handle.start() {
+			return
 		}
+		ctx = job.handle.ctx
 	}
-}
 
-func (ge *goExecutor) emitError(err error) {
-	ge.errorChsMutex.RLock()
-	defer ge.errorChsMutex.RUnlock()
+	started := time.Now()
+	err := ge.callJob(ctx, job.jobFn)
 
-	for _, ch := range ge.errorChs {
-		ch <- err
+	if job.handle != nil {
+		job.handle.finishRun(err)
+	}
+
+	if err != nil {
+		jobErr := newJobError(job, err)
+		jobErr.Started = started
+		jobErr.Finished = time.Now()
+		ge.emitError(jobErr)
 	}
 }
 
-func (ge *goExecutor) ErrorChan(ch chan error) {
-	ge.errorChsMutex.RLock()
-	defer ge.errorChsMutex.RUnlock()
+// callJob runs fn converting its panics into *PanicError
+func (ge *goExecutor) callJob(ctx context.Context, fn interfaces.JobFn) (err error) {
+	completed := false
+	defer ge.recoverJob(&err, &completed)
 
-	ge.errorChs = append(ge.errorChs, ch)
+	err = fn(ctx)
+	completed = true
+	return err
 }
 
-func (ge *goExecutor) PostJob(job interfaces.JobFn) error {
-	if ge.ctx.Err() != nil {
-		return ErrExecutorStopped
+// callJobWithResult same as callJob for jobs with result
+func (ge *goExecutor) callJobWithResult(ctx context.Context, fn interfaces.JobWithResultFn) (r interface{}, err error) {
+	completed := false
+	defer ge.recoverJob(&err, &completed)
+
+	r, err = fn(ctx)
+	completed = true
+	return r, err
+}
+
+// recoverJob must be deferred by the function calling the job, which sets completed after the job returns.
+// It stores the recovered panic in err, or panics again with WithRepanic so the crash shows the original stack.
+// completed tells panic(nil) apart from a normal return, recover returns nil for both before Go 1.21
+func (ge *goExecutor) recoverJob(err *error, completed *bool) {
+	if *completed {
+		return
 	}
 
-	jobSpec := &jobImpl{
-		jobFn: job,
+	v := recover()
+
+	if ge.repanic {
+		panic(v)
 	}
 
-	ge.queue.PushBack(jobSpec)
+	*err = &PanicError{
+		Value: v,
+		Stack: debug.Stack(),
+	}
+}
 
-	ge.hasJobsEvent.SetOne()
-	return nil
+// removeJob removes a job that didn't start from the queue or from the jobs waiting for a permit
+func (ge *goExecutor) removeJob(job *jobImpl) {
+	removed := ge.queue.Remove(func(other *jobImpl) bool {
+		return other == job
+	})
+
+	if removed > 0 {
+		if job.hasPermit {
+			ge.categories[job.category].Release(1)
+		}
+		ge.jobDone()
+		return
+	}
+
+	if ge.unpark(job) {
+		ge.jobDone()
+	}
+
+	// Otherwise a worker already took it and skips it when it sees the cancelled status
 }
 
-func (ge *goExecutor) CollectChan(jobs ...interfaces.JobWithResultFn) <-chan interface{} {
-	ch := make(chan interface{})
+// unpark removes a job waiting for a permit. Returns false if ShutdownNow took it already
+func (ge *goExecutor) unpark(job *jobImpl) bool {
+	ge.stateMutex.Lock()
+	defer ge.stateMutex.Unlock()
 
-	results := sync.Map{}
-	var currentPos int
-	closed := false
-	publishMutex := &sync.Mutex{}
+	if _, ok := ge.parked[job]; !ok {
+		return false
+	}
 
-	publish := func(pos int) {
-		publishMutex.Lock()
-		defer publishMutex.Unlock()
+	delete(ge.parked, job)
+	return true
+}
 
-		for ; currentPos < len(jobs); currentPos++ {
-			r, prs := results.Load(currentPos)
-			if !prs {
-				return
-			}
-			ch <- r
+// waitPermit waits a free slot of the job category and puts the job back in front of the queue
+func (ge *goExecutor) waitPermit(sem semaphore.Semaphore, job *jobImpl) {
+	if err := sem.Acquire(ge.ctx, 1); err != nil {
+		if ge.unpark(job) {
+			ge.abandonJob(job, ge.stoppedErr(err))
 		}
+		return
+	}
 
-		if !closed && currentPos == len(jobs) {
-			close(ch)
-			closed = true
-		}
+	if !ge.unpark(job) {
+		sem.Release(1)
+		return
 	}
 
-	for i, job := range jobs {
-		pos := i
-		jobFn := job
-		jobSpec := &jobImpl{
-			jobFn: func(ctx context.Context) error {
-				r, err := jobFn(ctx)
+	job.hasPermit = true
+	if err := ge.queue.PushFrontCtx(ge.ctx, job); err != nil {
+		ge.abandonJob(job, ge.stoppedErr(err))
+	}
+}
+
+// stoppedErr returns ErrExecutorStopped instead of err if the executor was stopped
+func (ge *goExecutor) stoppedErr(err error) error {
+	if ge.ctx.Err() != nil {
+		return ErrExecutorStopped
+	}
 
-				if ctx.Err() == nil {
-					results.Store(pos, r)
-					go publish(pos)
-				}
+	return err
+}
 
-				return err
-			},
-		}
+// abandonedErr is the error of the jobs left queued once the executor is stopped
+func (ge *goExecutor) abandonedErr() error {
+	ge.stateMutex.Lock()
+	defer ge.stateMutex.Unlock()
 
-		ge.queue.PushBack(jobSpec)
+	if ge.shutdownNow {
+		return ErrJobCancelled
 	}
 
-	ge.hasJobsEvent.Set()
+	return ErrExecutorStopped
+}
 
-	return (<-chan interface{})(ch)
+// dropJob is called for jobs dropped by the queue overflow policy
+func (ge *goExecutor) dropJob(job *jobImpl) {
+	ge.abandonJob(job, ErrJobDropped)
 }
 
-func (ge *goExecutor) CollectChanFirstServe(jobs ...interfaces.JobWithResultFn) <-chan *interfaces.JobResultIndexed {
-	ch := make(chan *interfaces.JobResultIndexed)
+// abandonJob reports an accepted job that won't run because of err
+func (ge *goExecutor) abandonJob(job *jobImpl, err error) {
+	if job.hasPermit {
+		ge.categories[job.category].Release(1)
+	}
 
-	var count int
-	publishMutex := &sync.Mutex{}
+	if job.onDrop != nil {
+		job.onDrop(err)
+	}
 
-	publish := func(r interface{}, pos int) {
-		publishMutex.Lock()
-		defer publishMutex.Unlock()
+	if job.handle != nil {
+		job.handle.finish(interfaces.JobFailed, err)
+	}
 
-		ch <- &interfaces.JobResultIndexed{
-			Result: r,
-			Index:  pos,
-		}
-		count++
+	ge.emitError(newJobError(job, err))
+	ge.jobDone()
+}
 
-		if count == len(jobs) {
-			close(ch)
-		}
+func (ge *goExecutor) PostJob(job interfaces.JobFn) error {
+	return ge.postJob(newJob(job))
+}
+
+func (ge *goExecutor) PostJobHandle(job interfaces.JobFn) (interfaces.JobHandle, error) {
+	jobSpec := newJob(job)
+	jobSpec.id = atomic.AddUint64(&ge.lastJobID, 1)
+
+	h := newJobHandle(ge, jobSpec.id, jobSpec)
+
+	if err := ge.postJob(jobSpec); err != nil {
+		h.cancel()
+		return nil, err
 	}
 
-	for i, job := range jobs {
-		pos := i
-		jobFn := job
-		jobSpec := &jobImpl{
-			jobFn: func(ctx context.Context) error {
-				r, err := jobFn(ctx)
+	return h, nil
+}
 
-				if ctx.Err() == nil {
-					go publish(r, pos)
-				}
+func (ge *goExecutor) PostJobCategory(category string, job interfaces.JobFn) error {
+	jobSpec := newJob(job)
+	jobSpec.category = category
 
-				return err
-			},
-		}
+	return ge.postJob(jobSpec)
+}
 
-		ge.queue.PushBack(jobSpec)
+func (ge *goExecutor) postJob(jobSpec *jobImpl) error {
+	if jobSpec.id == 0 {
+		jobSpec.id = atomic.AddUint64(&ge.lastJobID, 1)
 	}
 
-	ge.hasJobsEvent.Set()
+	ge.stateMutex.Lock()
+	if ge.closing || ge.ctx.Err() != nil {
+		ge.stateMutex.Unlock()
+		return ErrExecutorStopped
+	}
+	ge.pending++
+	ge.stateMutex.Unlock()
+
+	if err := ge.queue.PushBackCtx(ge.ctx, jobSpec); err != nil {
+		ge.jobDone()
 
-	return (<-chan *interfaces.JobResultIndexed)(ch)
+		if ge.ctx.Err() != nil {
+			return ErrExecutorStopped
+		}
+		return err
+	}
+
+	return nil
 }
 
-func (ge *goExecutor) Collect(jobs ...interfaces.JobWithResultFn) ([]interface{}, error) {
-	ch := ge.CollectChan(jobs...)
-	results := []interface{}{}
+// states of a job posted with Submit
+const (
+	submitQueued = iota
+	submitStarted
+	submitAbandoned
+)
+
+func (ge *goExecutor) Submit(job interfaces.JobWithResultFn) event.Future[interface{}] {
+	p := event.NewPromise[interface{}]()
+
+	// state is submitQueued until the job starts or the future is rejected because the executor stopped
+	state := int32(submitQueued)
+
+	jobSpec := newJob(func(ctx context.Context) error {
+		if !atomic.CompareAndSwapInt32(&state, submitQueued, submitStarted) {
+			return nil
+		}
+
+		r, err := ge.callJobWithResult(ctx, job)
+		if err != nil {
+			p.Reject(err)
+			return err
+		}
+
+		p.Resolve(r)
+		return nil
+	})
+	jobSpec.onDrop = func(err error) {
+		p.Reject(err)
+	}
 
-	for r := range ch {
-		results = append(results, r)
+	if err := ge.postJob(jobSpec); err != nil {
+		p.Reject(err)
+		return p.Future()
 	}
 
-	return results, nil
+	// The workers leave the queued jobs behind once the executor stops
+	go func() {
+		select {
+		case <-ge.ctx.Done():
+			if atomic.CompareAndSwapInt32(&state, submitQueued, submitAbandoned) {
+				p.Reject(ge.abandonedErr())
+			}
+		case <-p.Done():
+		}
+	}()
+
+	return p.Future()
 }
 
 func (ge *goExecutor) Len() int {
 	return ge.queue.Size()
 }
+
+func (ge *goExecutor) OldestPendingAge() time.Duration {
+	return ge.queue.OldestAge()
+}
+
+func (ge *goExecutor) QueueStats() queue.QueueStats {
+	return ge.queue.Stats()
+}
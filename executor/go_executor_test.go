@@ -3,9 +3,13 @@ package executor
 import (
 	"context"
 	"fmt"
+	"runtime"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/GustavoKatel/asyncutils/executor/interfaces"
+	"github.com/GustavoKatel/asyncutils/queue"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -94,6 +98,740 @@ func TestEnqueueStopped(t *testing.T) {
 	}))
 }
 
+func TestEnqueueQueueFull(t *testing.T) {
+	assert := assert.New(t)
+
+	exc, err := NewDefaultExecutor(1, WithQueueCapacity(1, queue.OverflowReject))
+	assert.Nil(err)
+
+	job := func(ctx context.Context) error {
+		return nil
+	}
+
+	// Not started, so nothing consumes the queue
+	assert.Nil(exc.PostJob(job))
+	assert.Equal(ErrQueueFull, exc.PostJob(job))
+	assert.Equal(1, exc.Len())
+}
+
+func TestEnqueueDropOldest(t *testing.T) {
+	assert := assert.New(t)
+
+	exc, err := NewDefaultExecutor(1, WithQueueCapacity(1, queue.OverflowDropOldest))
+	assert.Nil(err)
+
+	errCh := make(chan error, 1)
+	exc.ErrorChan(errCh)
+
+	results := make(chan int, 1)
+	assert.Nil(exc.PostJob(func(ctx context.Context) error {
+		results <- 1
+		return nil
+	}))
+	assert.Nil(exc.PostJob(func(ctx context.Context) error {
+		results <- 2
+		return nil
+	}))
+
+	assert.ErrorIs(<-errCh, ErrJobDropped)
+
+	assert.Nil(exc.Start())
+	defer exc.Stop()
+
+	assert.Equal(2, <-results)
+}
+
+func TestStopQueuedJobs(t *testing.T) {
+	assert := assert.New(t)
+
+	exc, err := NewDefaultExecutor(1)
+	assert.Nil(err)
+
+	assert.Nil(exc.Start())
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	assert.Nil(exc.PostJob(func(ctx context.Context) error {
+		close(started)
+		<-release
+		return nil
+	}))
+
+	var ran int32
+	for i := 0; i < 10; i++ {
+		assert.Nil(exc.PostJob(func(ctx context.Context) error {
+			atomic.AddInt32(&ran, 1)
+			return nil
+		}))
+	}
+
+	<-started
+	assert.Nil(exc.Stop())
+	close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.Nil(exc.Shutdown(ctx))
+
+	assert.Equal(int32(0), atomic.LoadInt32(&ran))
+	assert.Equal(10, exc.Len())
+}
+
+func TestOldestPendingAge(t *testing.T) {
+	assert := assert.New(t)
+
+	exc, err := NewDefaultExecutor(1)
+	assert.Nil(err)
+
+	assert.Equal(time.Duration(0), exc.OldestPendingAge())
+
+	done := make(chan struct{})
+	assert.Nil(exc.PostJob(func(ctx context.Context) error {
+		close(done)
+		return nil
+	}))
+
+	<-time.After(20 * time.Millisecond)
+	assert.True(exc.OldestPendingAge() >= 20*time.Millisecond)
+
+	assert.Nil(exc.Start())
+	defer exc.Stop()
+
+	<-done
+
+	stats := exc.QueueStats()
+	assert.Equal(uint64(1), stats.Enqueued)
+	assert.Equal(uint64(1), stats.Dequeued)
+	assert.Equal(time.Duration(0), exc.OldestPendingAge())
+}
+
+func TestSubmit(t *testing.T) {
+	assert := assert.New(t)
+
+	exc, err := NewDefaultExecutor(2)
+	assert.Nil(err)
+
+	errCh := make(chan error, 1)
+	exc.ErrorChan(errCh)
+
+	assert.Nil(exc.Start())
+	defer exc.Stop()
+
+	f := exc.Submit(func(ctx context.Context) (interface{}, error) {
+		return 42, nil
+	})
+
+	v, err := f.Get(context.Background())
+	assert.Nil(err)
+	assert.Equal(42, v)
+
+	expectedErr := fmt.Errorf("failed")
+	f = exc.Submit(func(ctx context.Context) (interface{}, error) {
+		return nil, expectedErr
+	})
+
+	_, err = f.Get(context.Background())
+	assert.Equal(expectedErr, err)
+	assert.ErrorIs(<-errCh, expectedErr)
+}
+
+func TestSubmitTyped(t *testing.T) {
+	assert := assert.New(t)
+
+	exc, err := NewDefaultExecutor(1)
+	assert.Nil(err)
+
+	assert.Nil(exc.Start())
+	defer exc.Stop()
+
+	f := SubmitTyped(exc, func(ctx context.Context) (string, error) {
+		return "result", nil
+	})
+
+	v, err := f.Get(context.Background())
+	assert.Nil(err)
+	assert.Equal("result", v)
+
+	errF := SubmitTyped(exc, func(ctx context.Context) (error, error) {
+		return nil, nil
+	})
+
+	e, err := errF.Get(context.Background())
+	assert.Nil(err)
+	assert.Nil(e)
+}
+
+func TestSubmitRejected(t *testing.T) {
+	assert := assert.New(t)
+
+	exc, err := NewDefaultExecutor(1, WithQueueCapacity(1, queue.OverflowDropNewest))
+	assert.Nil(err)
+
+	job := func(ctx context.Context) (interface{}, error) {
+		return nil, nil
+	}
+
+	// Not started, so the second job is dropped
+	pending := exc.Submit(job)
+	_, err = exc.Submit(job).Get(context.Background())
+	assert.Equal(ErrJobDropped, err)
+
+	assert.Nil(exc.Stop())
+	_, err = exc.Submit(job).Get(context.Background())
+	assert.Equal(ErrExecutorStopped, err)
+
+	// Left queued when the executor stopped
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err = pending.Get(ctx)
+	assert.Equal(ErrExecutorStopped, err)
+}
+
+func TestSubmitShutdownNow(t *testing.T) {
+	assert := assert.New(t)
+
+	exc, err := NewDefaultExecutor(1)
+	assert.Nil(err)
+
+	f := exc.Submit(func(ctx context.Context) (interface{}, error) {
+		return nil, nil
+	})
+
+	assert.Len(exc.ShutdownNow(), 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err = f.Get(ctx)
+	assert.Equal(ErrJobCancelled, err)
+}
+
+func TestCategoryLimit(t *testing.T) {
+	assert := assert.New(t)
+
+	exc, err := NewDefaultExecutor(4, WithCategoryLimit("db", 1))
+	assert.Nil(err)
+
+	assert.Nil(exc.Start())
+	defer exc.Stop()
+
+	release := make(chan struct{})
+	running := make(chan int, 10)
+
+	for i := 0; i < 3; i++ {
+		i := i
+		assert.Nil(exc.PostJobCategory("db", func(ctx context.Context) error {
+			running <- i
+			<-release
+			return nil
+		}))
+	}
+
+	// Waiting db jobs don't hold the other workers
+	done := make(chan struct{})
+	assert.Nil(exc.PostJob(func(ctx context.Context) error {
+		close(done)
+		return nil
+	}))
+	<-done
+
+	<-running
+	select {
+	case <-running:
+		assert.Fail("only one db job may run at a time")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	release <- struct{}{}
+	<-running
+	release <- struct{}{}
+	<-running
+	release <- struct{}{}
+
+	// Categories without a limit are not bounded
+	started := make(chan struct{}, 2)
+	for i := 0; i < 2; i++ {
+		assert.Nil(exc.PostJobCategory("other", func(ctx context.Context) error {
+			started <- struct{}{}
+			<-release
+			return nil
+		}))
+	}
+	<-started
+	<-started
+	close(release)
+}
+
+func TestShutdown(t *testing.T) {
+	assert := assert.New(t)
+
+	exc, err := NewDefaultExecutor(2)
+	assert.Nil(err)
+
+	assert.Nil(exc.Start())
+
+	results := make(chan int, 10)
+	for i := 0; i < 10; i++ {
+		i := i
+		assert.Nil(exc.PostJob(func(ctx context.Context) error {
+			<-time.After(5 * time.Millisecond)
+			results <- i
+			return nil
+		}))
+	}
+
+	// Queued jobs are drained, not abandoned
+	assert.Nil(exc.Shutdown(context.Background()))
+	assert.Equal(10, len(results))
+	assert.Equal(0, exc.Len())
+
+	assert.Equal(ErrExecutorStopped, exc.PostJob(func(ctx context.Context) error {
+		return nil
+	}))
+
+	// Already shut down
+	assert.Nil(exc.Shutdown(context.Background()))
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	exc, err := NewDefaultExecutor(1)
+	assert.Nil(err)
+
+	assert.Nil(exc.Start())
+	defer exc.Stop()
+
+	release := make(chan struct{})
+	assert.Nil(exc.PostJob(func(ctx context.Context) error {
+		<-release
+		return nil
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.Equal(context.DeadlineExceeded, exc.Shutdown(ctx))
+
+	// The job keeps running and a later Shutdown finishes
+	close(release)
+	assert.Nil(exc.Shutdown(context.Background()))
+}
+
+func TestShutdownAfterStop(t *testing.T) {
+	assert := assert.New(t)
+
+	exc, err := NewDefaultExecutor(1)
+	assert.Nil(err)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	assert.Nil(exc.PostJob(func(ctx context.Context) error {
+		close(started)
+		<-release
+		return nil
+	}))
+
+	var ran int32
+	assert.Nil(exc.PostJob(func(ctx context.Context) error {
+		atomic.StoreInt32(&ran, 1)
+		return nil
+	}))
+
+	assert.Nil(exc.Start())
+	<-started
+	assert.Nil(exc.Stop())
+	close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	assert.Nil(exc.Shutdown(ctx))
+	assert.Equal(int32(0), atomic.LoadInt32(&ran))
+}
+
+func TestShutdownNow(t *testing.T) {
+	assert := assert.New(t)
+
+	exc, err := NewDefaultExecutor(2, WithCategoryLimit("db", 1))
+	assert.Nil(err)
+
+	assert.Nil(exc.Start())
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	assert.Nil(exc.PostJobCategory("db", func(ctx context.Context) error {
+		close(started)
+		<-release
+		return nil
+	}))
+	<-started
+
+	// Waits for the db permit
+	ran := make(chan int, 2)
+	assert.Nil(exc.PostJobCategory("db", func(ctx context.Context) error {
+		ran <- 1
+		return nil
+	}))
+
+	// Keeps the other worker busy so the next job stays queued
+	blocking := make(chan struct{})
+	assert.Nil(exc.PostJob(func(ctx context.Context) error {
+		close(blocking)
+		<-release
+		return nil
+	}))
+	<-blocking
+
+	assert.Nil(exc.PostJob(func(ctx context.Context) error {
+		ran <- 2
+		return nil
+	}))
+
+	deadline := time.Now().Add(time.Second)
+	for exc.Len() != 1 && time.Now().Before(deadline) {
+		<-time.After(time.Millisecond)
+	}
+
+	jobs := exc.ShutdownNow()
+	assert.Equal(2, len(jobs))
+	close(release)
+
+	for _, job := range jobs {
+		assert.Nil(job(context.Background()))
+	}
+	assert.Equal(2, len(ran))
+
+	assert.Equal(ErrExecutorStopped, exc.PostJob(func(ctx context.Context) error {
+		return nil
+	}))
+	assert.Nil(exc.Shutdown(context.Background()))
+}
+
+func TestShutdownNowNotStarted(t *testing.T) {
+	assert := assert.New(t)
+
+	exc, err := NewDefaultExecutor(4)
+	assert.Nil(err)
+
+	assert.Nil(exc.Start())
+
+	var ran int32
+	for i := 0; i < 1000; i++ {
+		assert.Nil(exc.PostJob(func(ctx context.Context) error {
+			atomic.AddInt32(&ran, 1)
+			return nil
+		}))
+	}
+
+	jobs := exc.ShutdownNow()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.Nil(exc.Shutdown(ctx))
+
+	// Every job either ran or was returned
+	assert.Equal(1000, int(atomic.LoadInt32(&ran))+len(jobs))
+}
+
+func TestPostJobHandle(t *testing.T) {
+	assert := assert.New(t)
+
+	exc, err := NewDefaultExecutor(1)
+	assert.Nil(err)
+
+	release := make(chan struct{})
+	h, err := exc.PostJobHandle(func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+	assert.Nil(err)
+	assert.Equal(interfaces.JobQueued, h.Status())
+
+	failed, err := exc.PostJobHandle(func(ctx context.Context) error {
+		return fmt.Errorf("failed")
+	})
+	assert.Nil(err)
+	assert.NotEqual(h.ID(), failed.ID())
+
+	assert.Nil(exc.Start())
+	defer exc.Stop()
+
+	deadline := time.Now().Add(time.Second)
+	for h.Status() != interfaces.JobRunning && time.Now().Before(deadline) {
+		<-time.After(time.Millisecond)
+	}
+	assert.Equal(interfaces.JobRunning, h.Status())
+
+	<-time.After(10 * time.Millisecond)
+	close(release)
+
+	assert.Nil(h.Wait(context.Background()))
+	assert.Equal(interfaces.JobSucceeded, h.Status())
+
+	timings := h.Timings()
+	assert.True(timings.QueueTime() > 0)
+	assert.True(timings.RunTime() >= 10*time.Millisecond)
+
+	assert.Equal("failed", failed.Wait(context.Background()).Error())
+	<-failed.Done()
+	assert.Equal(interfaces.JobFailed, failed.Status())
+}
+
+func TestJobHandleCancelQueued(t *testing.T) {
+	assert := assert.New(t)
+
+	exc, err := NewDefaultExecutor(1)
+	assert.Nil(err)
+
+	ran := false
+	h, err := exc.PostJobHandle(func(ctx context.Context) error {
+		ran = true
+		return nil
+	})
+	assert.Nil(err)
+	assert.Equal(1, exc.Len())
+
+	h.Cancel()
+	assert.Equal(interfaces.JobCancelled, h.Status())
+	assert.Equal(ErrJobCancelled, h.Wait(context.Background()))
+
+	// Removed from the queue
+	assert.Equal(0, exc.Len())
+
+	assert.Nil(exc.Start())
+	assert.Nil(exc.Shutdown(context.Background()))
+	assert.False(ran)
+}
+
+func TestJobHandleCancelRunning(t *testing.T) {
+	assert := assert.New(t)
+
+	exc, err := NewDefaultExecutor(1)
+	assert.Nil(err)
+
+	assert.Nil(exc.Start())
+	defer exc.Stop()
+
+	started := make(chan struct{})
+	h, err := exc.PostJobHandle(func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	assert.Nil(err)
+
+	<-started
+	h.Cancel()
+
+	assert.Equal(context.Canceled, h.Wait(context.Background()))
+	assert.Equal(interfaces.JobCancelled, h.Status())
+
+	// Other jobs keep the executor context
+	other, err := exc.PostJobHandle(func(ctx context.Context) error {
+		return ctx.Err()
+	})
+	assert.Nil(err)
+	assert.Nil(other.Wait(context.Background()))
+}
+
+func TestJobHandleQueuedAfterStop(t *testing.T) {
+	assert := assert.New(t)
+
+	exc, err := NewDefaultExecutor(1)
+	assert.Nil(err)
+
+	assert.Nil(exc.Start())
+
+	started := make(chan struct{})
+	running, err := exc.PostJobHandle(func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	assert.Nil(err)
+	<-started
+
+	queued, err := exc.PostJobHandle(func(ctx context.Context) error {
+		return nil
+	})
+	assert.Nil(err)
+
+	assert.Nil(exc.Stop())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	assert.Equal(ErrExecutorStopped, queued.Wait(ctx))
+	assert.Equal(interfaces.JobCancelled, queued.Status())
+	assert.Equal(context.Canceled, running.Wait(ctx))
+}
+
+func TestJobHandleQueuedAfterShutdownNow(t *testing.T) {
+	assert := assert.New(t)
+
+	exc, err := NewDefaultExecutor(1)
+	assert.Nil(err)
+
+	h, err := exc.PostJobHandle(func(ctx context.Context) error {
+		return nil
+	})
+	assert.Nil(err)
+
+	assert.Len(exc.ShutdownNow(), 1)
+	assert.Equal(ErrJobCancelled, h.Wait(context.Background()))
+	assert.Equal(interfaces.JobCancelled, h.Status())
+}
+
+func TestJobHandleCancelWaitingPermit(t *testing.T) {
+	assert := assert.New(t)
+
+	exc, err := NewDefaultExecutor(2, WithCategoryLimit("db", 1))
+	assert.Nil(err)
+	ge := exc.(*goExecutor)
+
+	assert.Nil(exc.Start())
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	assert.Nil(exc.PostJobCategory("db", func(ctx context.Context) error {
+		close(started)
+		<-release
+		return nil
+	}))
+	<-started
+
+	// A db job with a handle, it waits for the permit outside the queue
+	ran := false
+	job := &jobImpl{
+		jobFn: func(ctx context.Context) error {
+			ran = true
+			return nil
+		},
+		category: "db",
+	}
+	h := newJobHandle(ge, 0, job)
+	assert.Nil(ge.postJob(job))
+
+	parked := func() int {
+		ge.stateMutex.Lock()
+		defer ge.stateMutex.Unlock()
+		return len(ge.parked)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for parked() != 1 && time.Now().Before(deadline) {
+		<-time.After(time.Millisecond)
+	}
+
+	h.Cancel()
+	assert.Equal(0, parked())
+	close(release)
+
+	assert.Equal(ErrJobCancelled, h.Wait(context.Background()))
+	assert.Nil(exc.Shutdown(context.Background()))
+	assert.False(ran)
+}
+
+func TestCategoryLimitInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	exc, err := NewDefaultExecutor(1, WithCategoryLimit("db", 0))
+	assert.Nil(exc)
+	assert.Equal(ErrInvalidCategoryLimit, err)
+}
+
+// postWaitingPermit posts a db job with a handle and waits until it waits for the permit outside the queue
+func postWaitingPermit(t *testing.T, ge *goExecutor) *jobHandleImpl {
+	job := newJob(func(ctx context.Context) error {
+		return nil
+	})
+	job.category = "db"
+
+	h := newJobHandle(ge, 0, job)
+	assert.Nil(t, ge.postJob(job))
+
+	parked := func() int {
+		ge.stateMutex.Lock()
+		defer ge.stateMutex.Unlock()
+		return len(ge.parked)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for parked() != 1 && time.Now().Before(deadline) {
+		<-time.After(time.Millisecond)
+	}
+
+	return h
+}
+
+func TestCategoryLimitStopWaitingPermit(t *testing.T) {
+	assert := assert.New(t)
+
+	exc, err := NewDefaultExecutor(2, WithCategoryLimit("db", 1))
+	assert.Nil(err)
+	ge := exc.(*goExecutor)
+
+	errCh := make(chan error, 1)
+	exc.ErrorChan(errCh)
+
+	assert.Nil(exc.Start())
+
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{})
+	assert.Nil(exc.PostJobCategory("db", func(ctx context.Context) error {
+		close(started)
+		<-release
+		return nil
+	}))
+	<-started
+
+	h := postWaitingPermit(t, ge)
+	assert.Nil(exc.Stop())
+
+	assert.Equal(ErrExecutorStopped, h.Wait(context.Background()))
+	assert.ErrorIs(<-errCh, ErrExecutorStopped)
+}
+
+func TestCategoryLimitQueueFull(t *testing.T) {
+	assert := assert.New(t)
+
+	exc, err := NewDefaultExecutor(1, WithCategoryLimit("db", 1), WithQueueCapacity(1, queue.OverflowReject))
+	assert.Nil(err)
+	ge := exc.(*goExecutor)
+
+	assert.Nil(exc.Start())
+	defer exc.Stop()
+
+	// Holds the db permit without holding a worker
+	sem := ge.categories["db"]
+	assert.Nil(sem.Acquire(context.Background(), 1))
+
+	h := postWaitingPermit(t, ge)
+
+	// Keeps the worker busy and the queue full
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{})
+	assert.Nil(exc.PostJob(func(ctx context.Context) error {
+		close(started)
+		<-release
+		return nil
+	}))
+	<-started
+	assert.Nil(exc.PostJob(func(ctx context.Context) error {
+		return nil
+	}))
+
+	// The waiting job gets the permit but can't go back to the queue
+	sem.Release(1)
+	assert.Equal(ErrQueueFull, h.Wait(context.Background()))
+	assert.Equal(interfaces.JobFailed, h.Status())
+	assert.Equal(1, sem.Available())
+}
+
 func TestCollectChan(t *testing.T) {
 	assert := assert.New(t)
 
@@ -113,10 +851,13 @@ func TestCollectChan(t *testing.T) {
 
 	results := exc.CollectChan(job1, job2)
 	r := <-results
-	assert.Equal(1, r)
+	assert.Equal(0, r.Index)
+	assert.Equal(1, r.Result)
+	assert.Nil(r.Err)
 
 	r = <-results
-	assert.Equal(2, r)
+	assert.Equal(1, r.Index)
+	assert.Equal(2, r.Result)
 
 	r, ok := <-results
 	assert.False(ok)
@@ -125,7 +866,8 @@ func TestCollectChan(t *testing.T) {
 func TestCollectChanFirstServe(t *testing.T) {
 	assert := assert.New(t)
 
-	exc, err := NewDefaultExecutor(1)
+	// Two workers, so the second job finishes first
+	exc, err := NewDefaultExecutor(2)
 	assert.Nil(err)
 
 	assert.Nil(exc.Start())
@@ -175,3 +917,436 @@ func TestCollect(t *testing.T) {
 	assert.Equal(1, results[0])
 	assert.Equal(2, results[1])
 }
+
+func TestJobError(t *testing.T) {
+	assert := assert.New(t)
+
+	exc, err := NewDefaultExecutor(1)
+	assert.Nil(err)
+
+	errCh := make(chan error, 1)
+	exc.SubscribeErrors(errCh, ErrorDeliveryBlock)
+
+	assert.Nil(exc.Start())
+	defer exc.Stop()
+
+	expectedErr := fmt.Errorf("failed")
+	h, err := exc.PostJobHandle(func(ctx context.Context) error {
+		return expectedErr
+	})
+	assert.Nil(err)
+
+	jobErr, ok := (<-errCh).(*JobError)
+	assert.True(ok)
+	assert.Equal(h.ID(), jobErr.JobID)
+	assert.Equal(-1, jobErr.Index)
+	assert.Equal(1, jobErr.Attempt)
+	assert.ErrorIs(jobErr, expectedErr)
+	assert.False(jobErr.Started.IsZero())
+	assert.False(jobErr.Finished.Before(jobErr.Started))
+
+	exc.CollectChanFirstServe(
+		func(ctx context.Context) (interface{}, error) {
+			return 1, nil
+		},
+		func(ctx context.Context) (interface{}, error) {
+			return nil, expectedErr
+		},
+	)
+
+	jobErr = (<-errCh).(*JobError)
+	assert.Equal(1, jobErr.Index)
+	assert.Greater(jobErr.JobID, h.ID())
+}
+
+func TestErrorChanUnread(t *testing.T) {
+	assert := assert.New(t)
+
+	exc, err := NewDefaultExecutor(1)
+	assert.Nil(err)
+
+	// Nobody reads these channels, the workers must not wait for them
+	exc.ErrorChan(make(chan error))
+	exc.SubscribeErrors(make(chan error), ErrorDeliveryDrop)
+
+	assert.Nil(exc.Start())
+
+	for i := 0; i < 3; i++ {
+		assert.Nil(exc.PostJob(func(ctx context.Context) error {
+			return fmt.Errorf("failed")
+		}))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	assert.Nil(exc.Shutdown(ctx))
+}
+
+func TestErrorChanBuffered(t *testing.T) {
+	assert := assert.New(t)
+
+	exc, err := NewDefaultExecutor(1, WithErrorBuffer(2))
+	assert.Nil(err)
+
+	errCh := make(chan error)
+	exc.ErrorChan(errCh)
+
+	assert.Nil(exc.Start())
+	defer exc.Stop()
+
+	for i := 0; i < 2; i++ {
+		i := i
+		assert.Nil(exc.PostJob(func(ctx context.Context) error {
+			return fmt.Errorf("failed %d", i)
+		}))
+	}
+
+	assert.Equal("failed 0", (<-errCh).Error())
+	assert.Equal("failed 1", (<-errCh).Error())
+}
+
+func TestSubscribeErrorsUnsubscribe(t *testing.T) {
+	assert := assert.New(t)
+
+	exc, err := NewDefaultExecutor(1)
+	assert.Nil(err)
+
+	errCh := make(chan error)
+	unsubscribe := exc.SubscribeErrors(errCh, ErrorDeliveryBlock)
+
+	assert.Nil(exc.Start())
+
+	blocked := make(chan struct{})
+	assert.Nil(exc.PostJob(func(ctx context.Context) error {
+		close(blocked)
+		return fmt.Errorf("failed")
+	}))
+
+	// The worker is waiting for errCh to be read, unsubscribing releases it
+	<-blocked
+	unsubscribe()
+	unsubscribe()
+
+	assert.Nil(exc.PostJob(func(ctx context.Context) error {
+		return fmt.Errorf("failed")
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	assert.Nil(exc.Shutdown(ctx))
+
+	select {
+	case err := <-errCh:
+		assert.Fail("unexpected error", err)
+	default:
+	}
+}
+
+func TestCollectErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	exc, err := NewDefaultExecutor(2)
+	assert.Nil(err)
+
+	assert.Nil(exc.Start())
+	defer exc.Stop()
+
+	expectedErr := fmt.Errorf("failed")
+	job := func(v int, err error) interfaces.JobWithResultFn {
+		return func(ctx context.Context) (interface{}, error) {
+			return v, err
+		}
+	}
+
+	results, err := exc.Collect(job(1, nil), job(0, expectedErr), job(3, nil), job(0, expectedErr))
+	assert.Equal([]interface{}{1, nil, 3, nil}, results)
+
+	var collectErr *CollectError
+	assert.ErrorAs(err, &collectErr)
+	assert.Equal(2, len(collectErr.Errors))
+	assert.Equal("2 jobs failed: job 1: failed; job 3: failed", err.Error())
+
+	var jobErr *JobError
+	assert.ErrorAs(collectErr.Errors[1], &jobErr)
+	assert.Equal(3, jobErr.Index)
+	assert.ErrorIs(jobErr, expectedErr)
+
+	// Without relying on the Go 1.20 multi error support of the errors package
+	assert.True(collectErr.Is(expectedErr))
+	assert.False(collectErr.Is(ErrJobDropped))
+	jobErr = nil
+	assert.True(collectErr.As(&jobErr))
+	assert.Equal(1, jobErr.Index)
+
+	results, err = exc.Collect()
+	assert.Nil(err)
+	assert.Equal(0, len(results))
+}
+
+func TestCollectChanErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	exc, err := NewDefaultExecutor(1)
+	assert.Nil(err)
+
+	assert.Nil(exc.Start())
+	defer exc.Stop()
+
+	expectedErr := fmt.Errorf("failed")
+	results := exc.CollectChan(
+		func(ctx context.Context) (interface{}, error) {
+			return nil, expectedErr
+		},
+		func(ctx context.Context) (interface{}, error) {
+			return 2, nil
+		},
+	)
+
+	r := <-results
+	assert.Equal(0, r.Index)
+	assert.Equal(expectedErr, r.Err)
+
+	r = <-results
+	assert.Equal(1, r.Index)
+	assert.Equal(2, r.Result)
+
+	_, ok := <-results
+	assert.False(ok)
+}
+
+func TestCollectFailFast(t *testing.T) {
+	assert := assert.New(t)
+
+	exc, err := NewDefaultExecutor(1, WithCollectMode(CollectFailFast))
+	assert.Nil(err)
+
+	assert.Nil(exc.Start())
+	defer exc.Stop()
+
+	expectedErr := fmt.Errorf("failed")
+	ran := make(chan int, 3)
+
+	results, err := exc.Collect(
+		func(ctx context.Context) (interface{}, error) {
+			ran <- 0
+			return 1, nil
+		},
+		func(ctx context.Context) (interface{}, error) {
+			ran <- 1
+			return nil, expectedErr
+		},
+		func(ctx context.Context) (interface{}, error) {
+			ran <- 2
+			return 3, nil
+		},
+	)
+
+	assert.Equal([]interface{}{1, nil, nil}, results)
+	assert.ErrorIs(err, expectedErr)
+
+	// The job after the failing one never runs
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.Nil(exc.Shutdown(ctx))
+
+	close(ran)
+	order := []int{}
+	for i := range ran {
+		order = append(order, i)
+	}
+	assert.Equal([]int{0, 1}, order)
+}
+
+func TestCollectExecutorStopped(t *testing.T) {
+	assert := assert.New(t)
+
+	exc, err := NewDefaultExecutor(1)
+	assert.Nil(err)
+
+	assert.Nil(exc.Start())
+
+	started := make(chan struct{})
+	results := exc.CollectChanFirstServe(
+		func(ctx context.Context) (interface{}, error) {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+		func(ctx context.Context) (interface{}, error) {
+			return 2, nil
+		},
+	)
+
+	<-started
+	exc.Stop()
+
+	count := 0
+	for r := range results {
+		count++
+		if r.Index == 1 {
+			assert.Equal(ErrExecutorStopped, r.Err)
+		}
+	}
+	assert.Equal(2, count)
+
+	// Posting fails, the results are still reported
+	_, err = exc.Collect(func(ctx context.Context) (interface{}, error) {
+		return 1, nil
+	})
+	assert.ErrorIs(err, ErrExecutorStopped)
+}
+
+func TestJobPanic(t *testing.T) {
+	assert := assert.New(t)
+
+	exc, err := NewDefaultExecutor(1)
+	assert.Nil(err)
+
+	errCh := make(chan error, 1)
+	exc.ErrorChan(errCh)
+
+	assert.Nil(exc.Start())
+	defer exc.Stop()
+
+	h, err := exc.PostJobHandle(func(ctx context.Context) error {
+		panic("boom")
+	})
+	assert.Nil(err)
+
+	var panicErr *PanicError
+	assert.ErrorAs(<-errCh, &panicErr)
+	assert.Equal("boom", panicErr.Value)
+	assert.Contains(string(panicErr.Stack), "TestJobPanic")
+	assert.Equal("Job panicked: boom", panicErr.Error())
+
+	assert.ErrorAs(h.Wait(context.Background()), &panicErr)
+	assert.Equal(interfaces.JobFailed, h.Status())
+
+	// The worker keeps running
+	expectedErr := fmt.Errorf("failed")
+	_, err = exc.Submit(func(ctx context.Context) (interface{}, error) {
+		panic(expectedErr)
+	}).Get(context.Background())
+	assert.ErrorAs(err, &panicErr)
+	assert.ErrorIs(err, expectedErr)
+	<-errCh
+
+	results, err := exc.Collect(
+		func(ctx context.Context) (interface{}, error) {
+			return 1, nil
+		},
+		func(ctx context.Context) (interface{}, error) {
+			panic("boom")
+		},
+	)
+	assert.Equal([]interface{}{1, nil}, results)
+	assert.ErrorAs(err, &panicErr)
+}
+
+func TestJobPanicNil(t *testing.T) {
+	assert := assert.New(t)
+
+	exc, err := NewDefaultExecutor(1)
+	assert.Nil(err)
+	ge := exc.(*goExecutor)
+
+	err = ge.callJob(context.Background(), func(ctx context.Context) error {
+		panic(nil)
+	})
+
+	var panicErr *PanicError
+	assert.ErrorAs(err, &panicErr)
+
+	_, err = ge.callJobWithResult(context.Background(), func(ctx context.Context) (interface{}, error) {
+		panic(nil)
+	})
+	assert.ErrorAs(err, &panicErr)
+
+	assert.Nil(ge.callJob(context.Background(), func(ctx context.Context) error {
+		return nil
+	}))
+}
+
+func TestJobRepanic(t *testing.T) {
+	assert := assert.New(t)
+
+	exc, err := NewDefaultExecutor(1, WithRepanic())
+	assert.Nil(err)
+
+	ge := exc.(*goExecutor)
+	assert.PanicsWithValue("boom", func() {
+		ge.callJob(context.Background(), func(ctx context.Context) error {
+			panic("boom")
+		})
+	})
+}
+
+func TestErrorChanAfterStop(t *testing.T) {
+	assert := assert.New(t)
+
+	exc, err := NewDefaultExecutor(1)
+	assert.Nil(err)
+
+	errCh := make(chan error)
+	exc.ErrorChan(errCh)
+
+	assert.Nil(exc.Start())
+
+	started := make(chan struct{})
+	assert.Nil(exc.PostJob(func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+
+	<-started
+	assert.Nil(exc.Stop())
+
+	// Reported after the executor stopped
+	assert.ErrorIs(<-errCh, context.Canceled)
+
+	// Registered after the executor stopped
+	lateCh := make(chan error)
+	exc.ErrorChan(lateCh)
+
+	r := <-exc.CollectChan(func(ctx context.Context) (interface{}, error) {
+		return nil, nil
+	})
+	assert.Equal(ErrExecutorStopped, r.Err)
+	assert.ErrorIs(<-lateCh, ErrExecutorStopped)
+}
+
+func TestErrorChanNoGoroutineLeak(t *testing.T) {
+	assert := assert.New(t)
+
+	before := runtime.NumGoroutine()
+
+	for i := 0; i < 100; i++ {
+		exc, err := NewDefaultExecutor(1)
+		assert.Nil(err)
+
+		errCh := make(chan error, 1)
+		exc.ErrorChan(errCh)
+
+		assert.Nil(exc.Start())
+
+		// Half of them deliver an error before shutting down
+		if i%2 == 0 {
+			assert.Nil(exc.PostJob(func(ctx context.Context) error {
+				return fmt.Errorf("job failed")
+			}))
+			assert.NotNil(<-errCh)
+		}
+
+		assert.Nil(exc.Shutdown(context.Background()))
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	assert.LessOrEqual(runtime.NumGoroutine(), before)
+}
@@ -1,5 +1,13 @@
 package interfaces
 
+import (
+	"context"
+	"time"
+
+	"github.com/GustavoKatel/asyncutils/event"
+	queueIfaces "github.com/GustavoKatel/asyncutils/queue/interfaces"
+)
+
 // Executor interface
 type Executor interface {
 	// Start starts the executor
@@ -8,23 +16,51 @@ type Executor interface {
 	// Stop stops the executor and all the pending jobs
 	Stop() error
 
+	// Shutdown stops accepting jobs and waits for the pending ones to finish and the workers to exit.
+	// Returns ctx.Err() if ctx is done first, in which case the pending jobs keep running
+	Shutdown(ctx context.Context) error
+
+	// ShutdownNow stops accepting jobs and the executor, returning the jobs that didn't start
+	ShutdownNow() []JobFn
+
 	// PostJob enqueue a job
 	PostJob(job JobFn) error
 
-	// Collect executes all jobs posted and return the results in order
-	// if an error happens, the resulting slice will contain less elements than jobs
-	// please check ErrorChan
+	// PostJobHandle enqueue a job and returns a handle to follow or cancel it
+	PostJobHandle(job JobFn) (JobHandle, error)
+
+	// PostJobCategory enqueue a job limited by the concurrency limit of its category, if there is one
+	PostJobCategory(category string, job JobFn) error
+
+	// Submit enqueue a job and returns a future completed with its result. The future is rejected
+	// if the job fails or can't be enqueued, with ErrExecutorStopped if Stop runs before the job starts and ErrJobCancelled
+	// if ShutdownNow does. Job errors are still sent to ErrorChan
+	Submit(job JobWithResultFn) event.Future[interface{}]
+
+	// Collect executes all jobs posted and return the results in order. The slice always has one element
+	// per job, nil for the jobs that failed. If any job fails it returns a *executor.CollectError
 	Collect(jobs ...JobWithResultFn) ([]interface{}, error)
 
-	// CollectChan same as Collect but return a channel with the results sorted
-	CollectChan(jobs ...JobWithResultFn) <-chan interface{}
+	// CollectChan same as Collect but return a channel with the results sorted. The channel is closed
+	// once every job is reported, or after the first error with CollectFailFast
+	CollectChan(jobs ...JobWithResultFn) <-chan *JobResultIndexed
 
-	// CollectChanFirstServe same as Collect but return a channel with the results not sorted
+	// CollectChanFirstServe same as CollectChan but the results are not sorted
 	CollectChanFirstServe(jobs ...JobWithResultFn) <-chan *JobResultIndexed
 
-	// ErrorChan registers an error emitting channel
+	// ErrorChan registers an error emitting channel, same as SubscribeErrors with ErrorDeliveryBuffered
 	ErrorChan(ch chan error)
 
+	// SubscribeErrors registers a channel receiving the errors of the jobs as *executor.JobError, also the ones
+	// reported after the executor stops. Returns a function to unregister it
+	SubscribeErrors(ch chan error, delivery ErrorDelivery) func()
+
 	// Len size of the pending queue
 	Len() int
+
+	// OldestPendingAge time the oldest pending job has been waiting in the queue, zero if there are none
+	OldestPendingAge() time.Duration
+
+	// QueueStats metrics of the pending queue
+	QueueStats() queueIfaces.QueueStats
 }
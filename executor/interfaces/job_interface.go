@@ -1,6 +1,9 @@
 package interfaces
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // JobFn job interface
 type JobFn func(ctx context.Context) error
@@ -12,4 +15,114 @@ type JobWithResultFn func(ctx context.Context) (interface{}, error)
 type JobResultIndexed struct {
 	Index  int
 	Result interface{}
+
+	// Err error returned by the job, or the reason it didn't run
+	Err error
 }
+
+// CollectMode decides what Collect does when a job fails
+type CollectMode int
+
+const (
+	// CollectAll waits all the jobs and reports every error
+	CollectAll CollectMode = iota
+
+	// CollectFailFast stops at the first error, cancelling the jobs that didn't finish
+	CollectFailFast
+)
+
+// JobStatus state of a job posted with PostJobHandle
+type JobStatus int
+
+const (
+	// JobQueued waiting in the queue
+	JobQueued JobStatus = iota
+
+	// JobRunning being executed by a worker
+	JobRunning
+
+	// JobSucceeded finished without error
+	JobSucceeded
+
+	// JobFailed finished with an error or dropped by the queue overflow policy
+	JobFailed
+
+	// JobCancelled cancelled before finishing
+	JobCancelled
+)
+
+func (s JobStatus) String() string {
+	switch s {
+	case JobQueued:
+		return "queued"
+	case JobRunning:
+		return "running"
+	case JobSucceeded:
+		return "succeeded"
+	case JobFailed:
+		return "failed"
+	case JobCancelled:
+		return "cancelled"
+	}
+	return "unknown"
+}
+
+// JobTimings when a job went through each state, zero if it didn't get there
+type JobTimings struct {
+	Posted   time.Time
+	Started  time.Time
+	Finished time.Time
+}
+
+// QueueTime time the job waited in the queue
+func (t JobTimings) QueueTime() time.Duration {
+	if t.Started.IsZero() {
+		return 0
+	}
+	return t.Started.Sub(t.Posted)
+}
+
+// RunTime time the job took to run
+func (t JobTimings) RunTime() time.Duration {
+	if t.Finished.IsZero() || t.Started.IsZero() {
+		return 0
+	}
+	return t.Finished.Sub(t.Started)
+}
+
+// JobHandle tracks a job posted with PostJobHandle
+type JobHandle interface {
+	// ID unique id of the job in its executor
+	ID() uint64
+
+	// Status current state of the job
+	Status() JobStatus
+
+	// Cancel removes the job from the queue if it didn't start, or cancels its context if it is running
+	Cancel()
+
+	// Wait waits the job to finish or ctx to be done. Returns the job error, ErrJobCancelled if it was cancelled
+	// before starting or by ShutdownNow, ErrExecutorStopped if Stop ran before it started, or ctx.Err() if ctx was done first
+	Wait(ctx context.Context) error
+
+	// Done returns a channel that is closed when the job finishes
+	Done() <-chan struct{}
+
+	// Timings when the job went through each state
+	Timings() JobTimings
+}
+
+// ErrorDelivery decides how errors are delivered to a channel registered with SubscribeErrors
+type ErrorDelivery int
+
+const (
+	// ErrorDeliveryBuffered keeps the errors in a buffer per channel, dropping the oldest ones when it is full.
+	// Workers never wait for the channel to be read
+	ErrorDeliveryBuffered ErrorDelivery = iota
+
+	// ErrorDeliveryBlock sends the errors from the worker that ran the job, which waits until the channel is read
+	ErrorDeliveryBlock
+
+	// ErrorDeliveryDrop sends the errors only if the channel is ready to receive them, dropping them otherwise
+	ErrorDeliveryDrop
+)
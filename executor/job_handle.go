@@ -0,0 +1,181 @@
+package executor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/GustavoKatel/asyncutils/event"
+	"github.com/GustavoKatel/asyncutils/executor/interfaces"
+)
+
+// JobHandle tracks a job posted with PostJobHandle
+type JobHandle interfaces.JobHandle
+
+var _ interfaces.JobHandle = &jobHandleImpl{}
+
+type jobHandleImpl struct {
+	id   uint64
+	ge   *goExecutor
+	job  *jobImpl
+	done event.Event
+
+	// ctx is passed to the job, Cancel cancels it
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mutex           *sync.Mutex
+	status          interfaces.JobStatus
+	cancelRequested bool
+	err             error
+	timings         interfaces.JobTimings
+}
+
+func newJobHandle(ge *goExecutor, id uint64, job *jobImpl) *jobHandleImpl {
+	ctx, cancel := context.WithCancel(ge.ctx)
+
+	h := &jobHandleImpl{
+		id:     id,
+		ge:     ge,
+		job:    job,
+		done:   event.NewEvent(false),
+		ctx:    ctx,
+		cancel: cancel,
+		mutex:  &sync.Mutex{},
+		status: interfaces.JobQueued,
+		timings: interfaces.JobTimings{
+			Posted: time.Now(),
+		},
+	}
+
+	job.handle = h
+
+	go h.watch()
+
+	return h
+}
+
+// watch finishes the handle if the executor stops while the job is still queued
+func (h *jobHandleImpl) watch() {
+	<-h.ctx.Done()
+
+	if h.ge.ctx.Err() == nil {
+		return
+	}
+
+	err := h.ge.abandonedErr()
+
+	h.mutex.Lock()
+	queued := h.status == interfaces.JobQueued
+	if queued {
+		h.status = interfaces.JobCancelled
+		h.err = err
+		h.timings.Finished = time.Now()
+	}
+	h.mutex.Unlock()
+
+	if queued {
+		h.done.Set()
+	}
+}
+
+// start moves the job to running. Returns false if it was cancelled while queued
+func (h *jobHandleImpl) start() bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.status != interfaces.JobQueued {
+		return false
+	}
+
+	h.status = interfaces.JobRunning
+	h.timings.Started = time.Now()
+	return true
+}
+
+// finish records the result of the job and releases the waiters
+func (h *jobHandleImpl) finish(status interfaces.JobStatus, err error) {
+	h.mutex.Lock()
+
+	if h.status == interfaces.JobQueued || h.status == interfaces.JobRunning {
+		h.status = status
+		h.err = err
+		h.timings.Finished = time.Now()
+	}
+
+	h.mutex.Unlock()
+
+	h.cancel()
+	h.done.Set()
+}
+
+// finishRun records the result of a job that ran
+func (h *jobHandleImpl) finishRun(err error) {
+	h.mutex.Lock()
+	cancelled := h.cancelRequested
+	h.mutex.Unlock()
+
+	switch {
+	case err == nil:
+		h.finish(interfaces.JobSucceeded, nil)
+	case cancelled:
+		h.finish(interfaces.JobCancelled, err)
+	default:
+		h.finish(interfaces.JobFailed, err)
+	}
+}
+
+func (h *jobHandleImpl) ID() uint64 {
+	return h.id
+}
+
+func (h *jobHandleImpl) Status() interfaces.JobStatus {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.status
+}
+
+func (h *jobHandleImpl) Cancel() {
+	h.mutex.Lock()
+
+	h.cancelRequested = true
+	status := h.status
+
+	if status == interfaces.JobQueued {
+		h.status = interfaces.JobCancelled
+		h.err = ErrJobCancelled
+		h.timings.Finished = time.Now()
+	}
+
+	h.mutex.Unlock()
+
+	switch status {
+	case interfaces.JobQueued:
+		h.ge.removeJob(h.job)
+		h.cancel()
+		h.done.Set()
+
+	case interfaces.JobRunning:
+		h.cancel()
+	}
+}
+
+func (h *jobHandleImpl) Wait(ctx context.Context) error {
+	if err := h.done.WaitContext(ctx); err != nil {
+		return err
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.err
+}
+
+func (h *jobHandleImpl) Done() <-chan struct{} {
+	return h.done.Done()
+}
+
+func (h *jobHandleImpl) Timings() interfaces.JobTimings {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.timings
+}
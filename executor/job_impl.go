@@ -4,4 +4,29 @@ import "github.com/GustavoKatel/asyncutils/executor/interfaces"
 
 type jobImpl struct {
 	jobFn interfaces.JobFn
+
+	// id unique id of the job, assigned when posted
+	id uint64
+
+	// index position of the job in its Collect call, -1 for other jobs
+	index int
+
+	// onDrop is called with the reason if the job is abandoned without running, may be nil
+	onDrop func(err error)
+
+	// category limits the concurrency of this job if it has a limit set with WithCategoryLimit
+	category string
+
+	// hasPermit is set once the job holds a permit of its category semaphore
+	hasPermit bool
+
+	// handle tracks jobs posted with PostJobHandle, nil otherwise
+	handle *jobHandleImpl
+}
+
+func newJob(jobFn interfaces.JobFn) *jobImpl {
+	return &jobImpl{
+		jobFn: jobFn,
+		index: -1,
+	}
 }
@@ -0,0 +1,54 @@
+package executor
+
+import (
+	"github.com/GustavoKatel/asyncutils/queue"
+	"github.com/GustavoKatel/asyncutils/semaphore"
+)
+
+// Option configures the default executor
+type Option func(ge *goExecutor)
+
+// WithQueueCapacity bounds the number of pending jobs, "policy" decides what happens when the queue is full.
+// With queue.OverflowReject PostJob returns ErrQueueFull, with queue.OverflowBlock it waits for free space.
+// Jobs dropped by the other policies are reported as ErrJobDropped through ErrorChan
+func WithQueueCapacity(capacity int, policy queue.OverflowPolicy) Option {
+	return func(ge *goExecutor) {
+		ge.queueCapacity = capacity
+		ge.queuePolicy = policy
+	}
+}
+
+// WithCategoryLimit bounds the number of jobs of "category" running at the same time, see PostJobCategory.
+// Jobs over the limit wait for a free slot without holding a worker. The executor constructor returns
+// ErrInvalidCategoryLimit if limit is lower than 1
+func WithCategoryLimit(category string, limit int) Option {
+	return func(ge *goExecutor) {
+		if limit < 1 {
+			ge.optionErr = ErrInvalidCategoryLimit
+			return
+		}
+
+		ge.categories[category] = semaphore.New(limit)
+	}
+}
+
+// WithErrorBuffer sets how many undelivered errors are kept per channel with ErrorDeliveryBuffered
+func WithErrorBuffer(size int) Option {
+	return func(ge *goExecutor) {
+		ge.errorBuffer = size
+	}
+}
+
+// WithCollectMode sets what Collect, CollectChan and CollectChanFirstServe do when a job fails, CollectAll by default
+func WithCollectMode(mode CollectMode) Option {
+	return func(ge *goExecutor) {
+		ge.collectMode = mode
+	}
+}
+
+// WithRepanic lets panics inside jobs crash the process instead of reporting them as *PanicError
+func WithRepanic() Option {
+	return func(ge *goExecutor) {
+		ge.repanic = true
+	}
+}
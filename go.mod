@@ -1,8 +1,14 @@
 module github.com/GustavoKatel/asyncutils
 
-go 1.12
+go 1.19
 
 require (
 	github.com/golang/mock v1.6.0
 	github.com/stretchr/testify v1.8.0
 )
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,8 @@
+package pool
+
+import "errors"
+
+var (
+	// ErrPoolClosed tried to use a pool after closing it
+	ErrPoolClosed = errors.New("Pool is closed")
+)
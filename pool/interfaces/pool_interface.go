@@ -0,0 +1,52 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+)
+
+// PoolStats metrics of a pool
+type PoolStats struct {
+	// InUse resources handed out by Get and not returned yet
+	InUse int
+
+	// Idle resources waiting to be reused
+	Idle int
+
+	// Created resources created by the factory
+	Created uint64
+
+	// Destroyed resources closed for any reason
+	Destroyed uint64
+
+	// Evicted idle resources closed after the idle timeout
+	Evicted uint64
+
+	// Unhealthy idle resources closed because the health check failed
+	Unhealthy uint64
+
+	// WaitCount calls to Get that had to wait for a resource to be returned
+	WaitCount uint64
+
+	// WaitDuration total time spent waiting in Get
+	WaitDuration time.Duration
+}
+
+// Pool bounded pool of reusable resources
+type Pool[T any] interface {
+	// Get takes an idle resource or creates a new one, waiting for a resource to be returned if the pool is full
+	Get(ctx context.Context) (T, error)
+
+	// Put returns a resource taken by Get to the pool
+	Put(v T)
+
+	// Discard destroys a resource taken by Get instead of returning it, freeing its slot
+	Discard(v T)
+
+	// Close destroys the idle resources. Resources returned afterwards are destroyed too, and the Get calls
+	// waiting for a resource return ErrPoolClosed
+	Close() error
+
+	// Stats metrics of this pool
+	Stats() PoolStats
+}
@@ -0,0 +1,36 @@
+package pool
+
+import "time"
+
+// DefaultMaxSize default maximum number of resources of a pool
+const DefaultMaxSize = 8
+
+// Option configures a pool
+type Option func(c *config)
+
+type config struct {
+	maxSize     int
+	maxIdle     int
+	idleTimeout time.Duration
+}
+
+// WithMaxSize bounds the number of resources, idle or in use
+func WithMaxSize(size int) Option {
+	return func(c *config) {
+		c.maxSize = size
+	}
+}
+
+// WithMaxIdle bounds the number of idle resources, the ones returned beyond it are destroyed. Defaults to the max size
+func WithMaxIdle(idle int) Option {
+	return func(c *config) {
+		c.maxIdle = idle
+	}
+}
+
+// WithIdleTimeout destroys resources that stay idle for longer than "timeout"
+func WithIdleTimeout(timeout time.Duration) Option {
+	return func(c *config) {
+		c.idleTimeout = timeout
+	}
+}
@@ -0,0 +1,328 @@
+package pool
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/GustavoKatel/asyncutils/pool/interfaces"
+	"github.com/GustavoKatel/asyncutils/semaphore"
+)
+
+// Pool bounded pool of reusable resources
+type Pool[T any] interfaces.Pool[T]
+
+// PoolStats metrics of a pool
+type PoolStats = interfaces.PoolStats
+
+// Factory manages the lifecycle of the resources of a pool
+type Factory[T any] struct {
+	// New creates a new resource
+	New func(ctx context.Context) (T, error)
+
+	// Destroy closes a resource, may be nil
+	Destroy func(v T)
+
+	// Check is called before handing out an idle resource, it is destroyed if an error is returned. May be nil
+	Check func(ctx context.Context, v T) error
+}
+
+var _ interfaces.Pool[int] = &poolImpl[int]{}
+
+// New creates a new pool of resources created by "factory"
+func New[T any](factory Factory[T], opts ...Option) Pool[T] {
+	c := config{
+		maxSize: DefaultMaxSize,
+	}
+
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	if c.maxSize < 1 {
+		c.maxSize = 1
+	}
+
+	if c.maxIdle <= 0 || c.maxIdle > c.maxSize {
+		c.maxIdle = c.maxSize
+	}
+
+	p := &poolImpl[T]{
+		mutex:   &sync.Mutex{},
+		factory: factory,
+		config:  c,
+		sem:     semaphore.New(c.maxSize),
+		stop:    make(chan struct{}),
+	}
+
+	if c.idleTimeout > 0 {
+		go p.janitor()
+	}
+
+	return p
+}
+
+type idleResource[T any] struct {
+	v     T
+	since time.Time
+}
+
+type poolImpl[T any] struct {
+	mutex   *sync.Mutex
+	factory Factory[T]
+	config  config
+
+	// sem holds one permit per resource in use, so Get waits while the pool is full
+	sem semaphore.Semaphore
+
+	// idle resources, the most recently returned last
+	idle   []idleResource[T]
+	closed bool
+	stop   chan struct{}
+
+	stats PoolStats
+}
+
+func (p *poolImpl[T]) Get(ctx context.Context) (T, error) {
+	var zero T
+
+	if p.isClosed() {
+		return zero, ErrPoolClosed
+	}
+
+	if !p.sem.TryAcquire(1) {
+		start := time.Now()
+		err := p.acquire(ctx)
+
+		p.mutex.Lock()
+		p.stats.WaitCount++
+		p.stats.WaitDuration += time.Since(start)
+		p.mutex.Unlock()
+
+		if err != nil {
+			return zero, err
+		}
+	}
+
+	for {
+		v, ok, err := p.takeIdle()
+		if err != nil {
+			p.sem.Release(1)
+			return zero, err
+		}
+
+		if !ok {
+			break
+		}
+
+		if p.factory.Check != nil {
+			if err := p.factory.Check(ctx, v); err != nil {
+				p.mutex.Lock()
+				p.stats.Unhealthy++
+				p.stats.InUse--
+				p.mutex.Unlock()
+
+				p.destroy(v)
+				continue
+			}
+		}
+
+		return v, nil
+	}
+
+	v, err := p.factory.New(ctx)
+	if err != nil {
+		p.mutex.Lock()
+		p.stats.InUse--
+		p.mutex.Unlock()
+
+		p.sem.Release(1)
+		return zero, err
+	}
+
+	p.mutex.Lock()
+	p.stats.Created++
+	p.mutex.Unlock()
+
+	return v, nil
+}
+
+// acquire waits for a free slot until ctx is done or the pool is closed
+func (p *poolImpl[T]) acquire(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		select {
+		case <-p.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	err := p.sem.Acquire(ctx, 1)
+	if err != nil && p.isClosed() {
+		return ErrPoolClosed
+	}
+
+	return err
+}
+
+// takeIdle pops the most recently returned resource that hasn't expired and marks a resource in use.
+// ok is false if there are no idle resources, in which case the caller creates the one in use
+func (p *poolImpl[T]) takeIdle() (v T, ok bool, err error) {
+	p.mutex.Lock()
+
+	if p.closed {
+		p.mutex.Unlock()
+		return v, false, ErrPoolClosed
+	}
+
+	p.stats.InUse++
+
+	expired := p.removeExpired(time.Now())
+
+	if n := len(p.idle); n > 0 {
+		v = p.idle[n-1].v
+		p.idle[n-1] = idleResource[T]{}
+		p.idle = p.idle[:n-1]
+		ok = true
+	}
+
+	p.mutex.Unlock()
+
+	for _, r := range expired {
+		p.destroy(r)
+	}
+
+	return v, ok, nil
+}
+
+// removeExpired removes the idle resources past the idle timeout. Must be called with the lock held
+func (p *poolImpl[T]) removeExpired(now time.Time) []T {
+	if p.config.idleTimeout <= 0 {
+		return nil
+	}
+
+	expired := []T{}
+	kept := p.idle[:0]
+
+	for _, r := range p.idle {
+		if now.Sub(r.since) > p.config.idleTimeout {
+			expired = append(expired, r.v)
+		} else {
+			kept = append(kept, r)
+		}
+	}
+
+	for i := len(kept); i < len(p.idle); i++ {
+		p.idle[i] = idleResource[T]{}
+	}
+
+	p.idle = kept
+	p.stats.Evicted += uint64(len(expired))
+
+	return expired
+}
+
+func (p *poolImpl[T]) janitor() {
+	interval := p.config.idleTimeout / 2
+	if interval < time.Millisecond {
+		interval = time.Millisecond
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case now := <-ticker.C:
+			p.mutex.Lock()
+			expired := p.removeExpired(now)
+			p.mutex.Unlock()
+
+			for _, v := range expired {
+				p.destroy(v)
+			}
+		}
+	}
+}
+
+func (p *poolImpl[T]) destroy(v T) {
+	p.mutex.Lock()
+	p.stats.Destroyed++
+	p.mutex.Unlock()
+
+	if p.factory.Destroy != nil {
+		p.factory.Destroy(v)
+	}
+}
+
+func (p *poolImpl[T]) isClosed() bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.closed
+}
+
+func (p *poolImpl[T]) Put(v T) {
+	p.mutex.Lock()
+
+	p.stats.InUse--
+
+	keep := !p.closed && len(p.idle) < p.config.maxIdle
+	if keep {
+		p.idle = append(p.idle, idleResource[T]{v: v, since: time.Now()})
+	}
+
+	p.mutex.Unlock()
+
+	if !keep {
+		p.destroy(v)
+	}
+
+	p.sem.Release(1)
+}
+
+func (p *poolImpl[T]) Discard(v T) {
+	p.mutex.Lock()
+	p.stats.InUse--
+	p.mutex.Unlock()
+
+	p.destroy(v)
+	p.sem.Release(1)
+}
+
+func (p *poolImpl[T]) Close() error {
+	p.mutex.Lock()
+
+	if p.closed {
+		p.mutex.Unlock()
+		return nil
+	}
+
+	p.closed = true
+	close(p.stop)
+
+	idle := p.idle
+	p.idle = nil
+
+	p.mutex.Unlock()
+
+	for _, r := range idle {
+		p.destroy(r.v)
+	}
+
+	return nil
+}
+
+func (p *poolImpl[T]) Stats() PoolStats {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	stats := p.stats
+	stats.Idle = len(p.idle)
+
+	return stats
+}
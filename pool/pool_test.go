@@ -0,0 +1,267 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type resource struct {
+	id      int
+	healthy bool
+}
+
+type testFactory struct {
+	created   int32
+	destroyed int32
+}
+
+func (f *testFactory) factory() Factory[*resource] {
+	return Factory[*resource]{
+		New: func(ctx context.Context) (*resource, error) {
+			return &resource{id: int(atomic.AddInt32(&f.created, 1)), healthy: true}, nil
+		},
+		Destroy: func(r *resource) {
+			atomic.AddInt32(&f.destroyed, 1)
+		},
+		Check: func(ctx context.Context, r *resource) error {
+			if !r.healthy {
+				return errors.New("unhealthy")
+			}
+			return nil
+		},
+	}
+}
+
+func TestGetPut(t *testing.T) {
+	assert := assert.New(t)
+	f := &testFactory{}
+	p := New(f.factory(), WithMaxSize(2))
+
+	r1, err := p.Get(context.Background())
+	assert.Nil(err)
+	r2, err := p.Get(context.Background())
+	assert.Nil(err)
+	assert.NotEqual(r1.id, r2.id)
+
+	stats := p.Stats()
+	assert.Equal(2, stats.InUse)
+	assert.Equal(uint64(2), stats.Created)
+
+	p.Put(r1)
+
+	// Idle resources are reused
+	r3, err := p.Get(context.Background())
+	assert.Nil(err)
+	assert.Equal(r1, r3)
+
+	p.Put(r2)
+	p.Put(r3)
+
+	stats = p.Stats()
+	assert.Equal(0, stats.InUse)
+	assert.Equal(2, stats.Idle)
+	assert.Equal(uint64(2), stats.Created)
+
+	assert.Nil(p.Close())
+	assert.Equal(int32(2), atomic.LoadInt32(&f.destroyed))
+
+	_, err = p.Get(context.Background())
+	assert.Equal(ErrPoolClosed, err)
+}
+
+func TestGetWaitsFull(t *testing.T) {
+	assert := assert.New(t)
+	f := &testFactory{}
+	p := New(f.factory(), WithMaxSize(1))
+	defer p.Close()
+
+	r, err := p.Get(context.Background())
+	assert.Nil(err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err = p.Get(ctx)
+	assert.Equal(context.DeadlineExceeded, err)
+
+	go func() {
+		<-time.After(10 * time.Millisecond)
+		p.Put(r)
+	}()
+
+	r2, err := p.Get(context.Background())
+	assert.Nil(err)
+	assert.Equal(r, r2)
+
+	stats := p.Stats()
+	assert.Equal(uint64(2), stats.WaitCount)
+	assert.True(stats.WaitDuration >= 20*time.Millisecond)
+	assert.Equal(uint64(1), stats.Created)
+}
+
+func TestCloseWakesGet(t *testing.T) {
+	assert := assert.New(t)
+	f := &testFactory{}
+	p := New(f.factory(), WithMaxSize(1))
+
+	r, err := p.Get(context.Background())
+	assert.Nil(err)
+
+	errCh := make(chan error)
+	go func() {
+		_, err := p.Get(context.Background())
+		errCh <- err
+	}()
+
+	sem := p.(*poolImpl[*resource]).sem
+	deadline := time.Now().Add(time.Second)
+	for sem.Waiting() == 0 && time.Now().Before(deadline) {
+		<-time.After(time.Millisecond)
+	}
+
+	assert.Nil(p.Close())
+
+	select {
+	case err := <-errCh:
+		assert.Equal(ErrPoolClosed, err)
+	case <-time.After(time.Second):
+		assert.Fail("Get still blocked after Close")
+	}
+
+	p.Put(r)
+	assert.Equal(int32(1), atomic.LoadInt32(&f.destroyed))
+}
+
+func TestHealthCheck(t *testing.T) {
+	assert := assert.New(t)
+	f := &testFactory{}
+	p := New(f.factory())
+	defer p.Close()
+
+	r, err := p.Get(context.Background())
+	assert.Nil(err)
+
+	r.healthy = false
+	p.Put(r)
+
+	r2, err := p.Get(context.Background())
+	assert.Nil(err)
+	assert.NotEqual(r.id, r2.id)
+
+	stats := p.Stats()
+	assert.Equal(uint64(1), stats.Unhealthy)
+	assert.Equal(uint64(1), stats.Destroyed)
+	assert.Equal(1, stats.InUse)
+}
+
+func TestDiscardAndMaxIdle(t *testing.T) {
+	assert := assert.New(t)
+	f := &testFactory{}
+	p := New(f.factory(), WithMaxSize(2), WithMaxIdle(1))
+	defer p.Close()
+
+	r1, _ := p.Get(context.Background())
+	r2, _ := p.Get(context.Background())
+
+	p.Discard(r1)
+	p.Put(r2)
+
+	r3, _ := p.Get(context.Background())
+	r4, _ := p.Get(context.Background())
+	assert.Equal(r2, r3)
+
+	p.Put(r3)
+	p.Put(r4)
+
+	stats := p.Stats()
+	assert.Equal(1, stats.Idle)
+	assert.Equal(uint64(2), stats.Destroyed)
+	assert.Equal(int32(2), atomic.LoadInt32(&f.destroyed))
+}
+
+func TestIdleTimeout(t *testing.T) {
+	assert := assert.New(t)
+	f := &testFactory{}
+	p := New(f.factory(), WithIdleTimeout(10*time.Millisecond))
+	defer p.Close()
+
+	r, _ := p.Get(context.Background())
+	p.Put(r)
+
+	deadline := time.Now().Add(time.Second)
+	for p.Stats().Idle > 0 && time.Now().Before(deadline) {
+		<-time.After(5 * time.Millisecond)
+	}
+
+	stats := p.Stats()
+	assert.Equal(0, stats.Idle)
+	assert.Equal(uint64(1), stats.Evicted)
+	assert.Equal(int32(1), atomic.LoadInt32(&f.destroyed))
+}
+
+func TestFactoryError(t *testing.T) {
+	assert := assert.New(t)
+	expected := errors.New("failed")
+
+	p := New(Factory[int]{
+		New: func(ctx context.Context) (int, error) {
+			return 0, expected
+		},
+	}, WithMaxSize(1))
+	defer p.Close()
+
+	_, err := p.Get(context.Background())
+	assert.Equal(expected, err)
+
+	// The slot is released
+	_, err = p.Get(context.Background())
+	assert.Equal(expected, err)
+	assert.Equal(0, p.Stats().InUse)
+}
+
+func TestPoolContention(t *testing.T) {
+	assert := assert.New(t)
+	f := &testFactory{}
+	p := New(f.factory(), WithMaxSize(4))
+	defer p.Close()
+
+	var inUse, maxInUse int32
+	var wg sync.WaitGroup
+
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 20; j++ {
+				r, err := p.Get(context.Background())
+				if !assert.Nil(err) {
+					return
+				}
+
+				current := atomic.AddInt32(&inUse, 1)
+				for {
+					old := atomic.LoadInt32(&maxInUse)
+					if current <= old || atomic.CompareAndSwapInt32(&maxInUse, old, current) {
+						break
+					}
+				}
+
+				atomic.AddInt32(&inUse, -1)
+				p.Put(r)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	assert.LessOrEqual(atomic.LoadInt32(&maxInUse), int32(4))
+	assert.LessOrEqual(atomic.LoadInt32(&f.created), int32(4))
+	assert.Equal(0, p.Stats().InUse)
+}
@@ -0,0 +1,337 @@
+package queue
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/GustavoKatel/asyncutils/queue/interfaces"
+)
+
+// BlockingQueue thread-safe type-safe queue with blocking, timed and context-aware operations
+type BlockingQueue[T any] interfaces.TypedBlockingQueue[T]
+
+var _ interfaces.TypedBlockingQueue[int] = &boundedQueueImpl[int]{}
+
+// OverflowPolicy decides what happens when pushing to a full queue
+type OverflowPolicy int
+
+const (
+	// OverflowBlock waits for free space
+	OverflowBlock OverflowPolicy = iota
+	// OverflowReject rejects the incoming element with ErrQueueFull
+	OverflowReject
+	// OverflowDropOldest evicts the element at the head of the queue to make room for the incoming one
+	OverflowDropOldest
+	// OverflowDropNewest drops the incoming element
+	OverflowDropNewest
+)
+
+type boundedQueueImpl[T any] struct {
+	d        *deque[T]
+	capacity int
+	mutex    *sync.Mutex
+
+	policy  OverflowPolicy
+	onEvict func(el T)
+
+	// notEmpty and notFull are closed and replaced to wake up waiters,
+	// but only when someone is actually waiting on them
+	notEmpty       chan struct{}
+	notEmptyWaiter int
+	notFull        chan struct{}
+	notFullWaiter  int
+}
+
+// NewBounded creates a new blocking queue holding up to "capacity" elements.
+// A capacity lower or equal to zero creates an unbounded queue, in which case only pops block
+func NewBounded[T any](capacity int) BlockingQueue[T] {
+	return NewBoundedWithPolicy[T](capacity, OverflowBlock, nil)
+}
+
+// NewBoundedWithPolicy creates a new blocking queue holding up to "capacity" elements, "policy" decides what
+// happens when it is full. Every element dropped by the policy is handed to "onEvict", which may be nil.
+// PushBack and PushFront can't report errors, so the elements they reject are handed to "onEvict" as well
+func NewBoundedWithPolicy[T any](capacity int, policy OverflowPolicy, onEvict func(el T)) BlockingQueue[T] {
+	if capacity < 0 {
+		capacity = 0
+	}
+
+	return &boundedQueueImpl[T]{
+		d:        newDeque[T](capacity),
+		capacity: capacity,
+		mutex:    &sync.Mutex{},
+
+		policy:  policy,
+		onEvict: onEvict,
+
+		notEmpty: make(chan struct{}),
+		notFull:  make(chan struct{}),
+	}
+}
+
+func (q *boundedQueueImpl[T]) full() bool {
+	return q.capacity > 0 && q.d.len() >= q.capacity
+}
+
+func (q *boundedQueueImpl[T]) signalNotEmpty() {
+	if q.notEmptyWaiter > 0 {
+		close(q.notEmpty)
+		q.notEmpty = make(chan struct{})
+		q.notEmptyWaiter = 0
+	}
+}
+
+func (q *boundedQueueImpl[T]) signalNotFull() {
+	if q.notFullWaiter > 0 {
+		close(q.notFull)
+		q.notFull = make(chan struct{})
+		q.notFullWaiter = 0
+	}
+}
+
+func (q *boundedQueueImpl[T]) evict(el T) {
+	if q.onEvict != nil {
+		q.onEvict(el)
+	}
+}
+
+// push adds an element applying the overflow policy. Only OverflowBlock waits, and only if "block" is set.
+// Returns whether the element was added, OverflowDropNewest drops it without an error
+func (q *boundedQueueImpl[T]) push(ctx context.Context, el T, front bool, block bool) (bool, error) {
+	q.mutex.Lock()
+
+	var evicted T
+	hasEvicted := false
+
+	for q.full() {
+		switch q.policy {
+		case OverflowReject:
+			q.mutex.Unlock()
+			return false, ErrQueueFull
+
+		case OverflowDropNewest:
+			q.mutex.Unlock()
+			q.evict(el)
+			return false, nil
+
+		case OverflowDropOldest:
+			evicted, hasEvicted = q.d.popFront()
+			continue
+		}
+
+		if !block {
+			q.mutex.Unlock()
+			return false, ErrQueueFull
+		}
+
+		ch := q.notFull
+		q.notFullWaiter++
+		q.mutex.Unlock()
+
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+
+		q.mutex.Lock()
+	}
+
+	if front {
+		q.d.pushFront(el)
+	} else {
+		q.d.pushBack(el)
+	}
+
+	q.signalNotEmpty()
+	q.mutex.Unlock()
+
+	if hasEvicted {
+		q.evict(evicted)
+	}
+
+	return true, nil
+}
+
+func (q *boundedQueueImpl[T]) PushBack(el T) {
+	if _, err := q.push(context.Background(), el, false, true); err == ErrQueueFull {
+		q.evict(el)
+	}
+}
+
+func (q *boundedQueueImpl[T]) PushFront(el T) {
+	if _, err := q.push(context.Background(), el, true, true); err == ErrQueueFull {
+		q.evict(el)
+	}
+}
+
+func (q *boundedQueueImpl[T]) PushBackCtx(ctx context.Context, el T) error {
+	_, err := q.push(ctx, el, false, true)
+	return err
+}
+
+func (q *boundedQueueImpl[T]) PushFrontCtx(ctx context.Context, el T) error {
+	_, err := q.push(ctx, el, true, true)
+	return err
+}
+
+func (q *boundedQueueImpl[T]) PushBackTimeout(el T, d time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+
+	_, err := q.push(ctx, el, false, true)
+	return err
+}
+
+// TryPushBack applies the overflow policy without waiting
+func (q *boundedQueueImpl[T]) TryPushBack(el T) bool {
+	added, _ := q.push(context.Background(), el, false, false)
+	return added
+}
+
+func (q *boundedQueueImpl[T]) PopBack() (T, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	el, ok := q.d.popBack()
+	if ok {
+		q.signalNotFull()
+	}
+
+	return el, ok
+}
+
+func (q *boundedQueueImpl[T]) PopFront() (T, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	el, ok := q.d.popFront()
+	if ok {
+		q.signalNotFull()
+	}
+
+	return el, ok
+}
+
+func (q *boundedQueueImpl[T]) TryPopFront() (T, bool) {
+	return q.PopFront()
+}
+
+// PopFrontCtx waits for an element or ctx to be done. Once ctx is done nothing is popped, even if the queue
+// has elements. The check is done with the lock held, so DrainTo after cancelling ctx sees every element left
+func (q *boundedQueueImpl[T]) PopFrontCtx(ctx context.Context) (T, error) {
+	q.mutex.Lock()
+
+	for ctx.Err() != nil || q.d.len() == 0 {
+		if err := ctx.Err(); err != nil {
+			q.mutex.Unlock()
+			var zero T
+			return zero, err
+		}
+
+		ch := q.notEmpty
+		q.notEmptyWaiter++
+		q.mutex.Unlock()
+
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+
+		q.mutex.Lock()
+	}
+	defer q.mutex.Unlock()
+
+	el, _ := q.d.popFront()
+	q.signalNotFull()
+
+	return el, nil
+}
+
+func (q *boundedQueueImpl[T]) PopFrontTimeout(d time.Duration) (T, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+
+	return q.PopFrontCtx(ctx)
+}
+
+func (q *boundedQueueImpl[T]) Get(pos int) (T, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return q.d.get(pos)
+}
+
+func (q *boundedQueueImpl[T]) Size() int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return q.d.len()
+}
+
+func (q *boundedQueueImpl[T]) Capacity() int {
+	return q.capacity
+}
+
+// PushBackAll pushes the elements one by one, applying the overflow policy to each of them
+func (q *boundedQueueImpl[T]) PushBackAll(els ...T) {
+	for _, el := range els {
+		q.PushBack(el)
+	}
+}
+
+func (q *boundedQueueImpl[T]) DrainTo(n int) []T {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	els := q.d.drain(n)
+	if len(els) > 0 {
+		q.signalNotFull()
+	}
+
+	return els
+}
+
+func (q *boundedQueueImpl[T]) Snapshot() []T {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return q.d.snapshot()
+}
+
+func (q *boundedQueueImpl[T]) Range(fn func(i int, el T) bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.d.each(fn)
+}
+
+func (q *boundedQueueImpl[T]) Clear() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.d.clear()
+	q.signalNotFull()
+}
+
+func (q *boundedQueueImpl[T]) Remove(pred func(el T) bool) int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	removed := q.d.removeIf(pred)
+	if removed > 0 {
+		q.signalNotFull()
+	}
+
+	return removed
+}
+
+func (q *boundedQueueImpl[T]) IndexOf(pred func(el T) bool) int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return q.d.indexOf(pred)
+}
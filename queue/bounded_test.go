@@ -0,0 +1,217 @@
+package queue
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoundedTryPush(t *testing.T) {
+	assert := assert.New(t)
+	q := NewBounded[int](2)
+
+	assert.Equal(2, q.Capacity())
+
+	assert.True(q.TryPushBack(1))
+	assert.True(q.TryPushBack(2))
+	assert.False(q.TryPushBack(3))
+	assert.Equal(2, q.Size())
+
+	el, ok := q.TryPopFront()
+	assert.True(ok)
+	assert.Equal(1, el)
+
+	assert.True(q.TryPushBack(3))
+}
+
+func TestBoundedTryPopEmpty(t *testing.T) {
+	assert := assert.New(t)
+	q := NewBounded[int](2)
+
+	_, ok := q.TryPopFront()
+	assert.False(ok)
+}
+
+func TestBoundedPushBlocksWhenFull(t *testing.T) {
+	assert := assert.New(t)
+	q := NewBounded[int](1)
+
+	q.PushBack(1)
+
+	pushed := make(chan struct{})
+	go func() {
+		q.PushBack(2)
+		close(pushed)
+	}()
+
+	select {
+	case <-pushed:
+		assert.Fail("push should block while the queue is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	el, ok := q.PopFront()
+	assert.True(ok)
+	assert.Equal(1, el)
+
+	<-pushed
+
+	el, ok = q.PopFront()
+	assert.True(ok)
+	assert.Equal(2, el)
+}
+
+func TestBoundedPushCtx(t *testing.T) {
+	assert := assert.New(t)
+	q := NewBounded[int](1)
+
+	assert.Nil(q.PushBackCtx(context.Background(), 1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.Equal(context.Canceled, q.PushBackCtx(ctx, 2))
+	assert.Equal(context.DeadlineExceeded, q.PushBackTimeout(2, 10*time.Millisecond))
+	assert.Equal(1, q.Size())
+}
+
+func TestBoundedPopCtx(t *testing.T) {
+	assert := assert.New(t)
+	q := NewBounded[string](0)
+
+	go func() {
+		<-time.After(20 * time.Millisecond)
+		q.PushBack("abc")
+	}()
+
+	el, err := q.PopFrontCtx(context.Background())
+	assert.Nil(err)
+	assert.Equal("abc", el)
+
+	_, err = q.PopFrontTimeout(10 * time.Millisecond)
+	assert.Equal(context.DeadlineExceeded, err)
+}
+
+func TestBoundedPopCtxDone(t *testing.T) {
+	assert := assert.New(t)
+	q := NewBounded[string](0)
+	q.PushBack("abc")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Nothing is popped once ctx is done
+	_, err := q.PopFrontCtx(ctx)
+	assert.Equal(context.Canceled, err)
+	assert.Equal(1, q.Size())
+}
+
+func TestBoundedUnbounded(t *testing.T) {
+	assert := assert.New(t)
+	q := NewBounded[int](0)
+
+	assert.Equal(0, q.Capacity())
+
+	for i := 0; i < 1000; i++ {
+		assert.True(q.TryPushBack(i))
+	}
+
+	assert.Equal(1000, q.Size())
+}
+
+func TestBoundedProducersConsumers(t *testing.T) {
+	assert := assert.New(t)
+	q := NewBounded[int](4)
+
+	const producers = 4
+	const perProducer = 250
+
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				assert.Nil(q.PushBackCtx(context.Background(), 1))
+			}
+		}()
+	}
+
+	results := make(chan int)
+	for c := 0; c < 2; c++ {
+		go func() {
+			sum := 0
+			for {
+				el, err := q.PopFrontTimeout(100 * time.Millisecond)
+				if err != nil {
+					results <- sum
+					return
+				}
+				sum += el
+			}
+		}()
+	}
+
+	wg.Wait()
+	assert.Equal(producers*perProducer, <-results+<-results)
+}
+
+func TestBoundedOverflowReject(t *testing.T) {
+	assert := assert.New(t)
+
+	evicted := []int{}
+	q := NewBoundedWithPolicy(1, OverflowReject, func(el int) { evicted = append(evicted, el) })
+
+	assert.Nil(q.PushBackCtx(context.Background(), 1))
+	assert.Equal(ErrQueueFull, q.PushBackCtx(context.Background(), 2))
+	assert.Equal(ErrQueueFull, q.PushFrontCtx(context.Background(), 3))
+	assert.False(q.TryPushBack(4))
+
+	// PushBack can't return the error, the element goes to the eviction callback
+	q.PushBack(5)
+
+	assert.Equal([]int{1}, q.Snapshot())
+	assert.Equal([]int{5}, evicted)
+}
+
+func TestBoundedOverflowDropOldest(t *testing.T) {
+	assert := assert.New(t)
+
+	evicted := []int{}
+	q := NewBoundedWithPolicy(2, OverflowDropOldest, func(el int) { evicted = append(evicted, el) })
+
+	q.PushBack(1)
+	q.PushBack(2)
+	assert.Nil(q.PushBackCtx(context.Background(), 3))
+	assert.True(q.TryPushBack(4))
+
+	assert.Equal([]int{3, 4}, q.Snapshot())
+	assert.Equal([]int{1, 2}, evicted)
+}
+
+func TestBoundedOverflowDropNewest(t *testing.T) {
+	assert := assert.New(t)
+
+	evicted := []int{}
+	q := NewBoundedWithPolicy(2, OverflowDropNewest, func(el int) { evicted = append(evicted, el) })
+
+	q.PushBack(1)
+	q.PushBack(2)
+	assert.Nil(q.PushBackCtx(context.Background(), 3))
+	assert.False(q.TryPushBack(4))
+
+	assert.Equal([]int{1, 2}, q.Snapshot())
+	assert.Equal([]int{3, 4}, evicted)
+}
+
+func TestBoundedOverflowBlockTry(t *testing.T) {
+	assert := assert.New(t)
+	q := NewBoundedWithPolicy[int](1, OverflowBlock, nil)
+
+	assert.True(q.TryPushBack(1))
+	assert.False(q.TryPushBack(2))
+	assert.Equal(context.DeadlineExceeded, q.PushBackTimeout(2, 10*time.Millisecond))
+}
@@ -0,0 +1,55 @@
+package queue
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/GustavoKatel/asyncutils/queue/interfaces"
+)
+
+// Codec serializes queue elements to be stored by persistent queues
+type Codec interfaces.Codec
+
+var _ interfaces.Codec = StringCodec{}
+var _ interfaces.Codec = JSONCodec{}
+
+// StringCodec stores string elements as they are
+type StringCodec struct{}
+
+func (StringCodec) Encode(el interface{}) ([]byte, error) {
+	s, ok := el.(string)
+	if !ok {
+		return nil, fmt.Errorf("StringCodec: unsupported element type %T", el)
+	}
+
+	return []byte(s), nil
+}
+
+func (StringCodec) Decode(data []byte) (interface{}, error) {
+	return string(data), nil
+}
+
+// JSONCodec stores elements as json. If New is set, elements are decoded into the pointer it returns,
+// otherwise they are decoded as generic json values (map[string]interface{}, float64, ...)
+type JSONCodec struct {
+	New func() interface{}
+}
+
+func (JSONCodec) Encode(el interface{}) ([]byte, error) {
+	return json.Marshal(el)
+}
+
+func (c JSONCodec) Decode(data []byte) (interface{}, error) {
+	if c.New == nil {
+		var el interface{}
+		err := json.Unmarshal(data, &el)
+		return el, err
+	}
+
+	el := c.New()
+	if err := json.Unmarshal(data, el); err != nil {
+		return nil, err
+	}
+
+	return el, nil
+}
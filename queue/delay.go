@@ -0,0 +1,161 @@
+package queue
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/GustavoKatel/asyncutils/queue/interfaces"
+)
+
+var _ interfaces.DelayQueue = &delayQueueImpl{}
+
+type delayItem struct {
+	el       interface{}
+	deadline time.Time
+}
+
+type delayQueueImpl struct {
+	h     *priorityHeap[*delayItem]
+	mutex *sync.Mutex
+
+	// wake is closed and replaced whenever Take callers must look at the head again:
+	// the timer fired or an element with an earlier deadline was pushed
+	wake chan struct{}
+
+	// timer is shared by all Take callers and armed for the earliest deadline
+	timer   *time.Timer
+	armed   bool
+	armedAt time.Time
+	waiters int
+}
+
+// NewDelay creates a new delay queue
+func NewDelay() interfaces.DelayQueue {
+	q := &delayQueueImpl{
+		h: newPriorityHeap(func(a, b *delayItem) bool {
+			return a.deadline.Before(b.deadline)
+		}),
+		mutex: &sync.Mutex{},
+		wake:  make(chan struct{}),
+	}
+
+	q.timer = time.AfterFunc(time.Hour, q.fire)
+	q.timer.Stop()
+
+	return q
+}
+
+func (q *delayQueueImpl) fire() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.armed = false
+	q.signal()
+}
+
+// signal wakes up all Take callers. Must be called with the lock held
+func (q *delayQueueImpl) signal() {
+	close(q.wake)
+	q.wake = make(chan struct{})
+}
+
+// arm makes sure the timer fires at "deadline" or earlier. Must be called with the lock held
+func (q *delayQueueImpl) arm(deadline time.Time) {
+	if q.armed && !deadline.Before(q.armedAt) {
+		return
+	}
+
+	q.armed = true
+	q.armedAt = deadline
+	q.timer.Reset(time.Until(deadline))
+}
+
+func (q *delayQueueImpl) PushAt(el interface{}, deadline time.Time) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	item := q.h.pushBack(&delayItem{el: el, deadline: deadline})
+
+	// The new element became the head, waiters must pick the new deadline
+	if q.waiters > 0 && q.h.items[0] == item {
+		q.signal()
+	}
+}
+
+func (q *delayQueueImpl) PushAfter(el interface{}, d time.Duration) {
+	q.PushAt(el, time.Now().Add(d))
+}
+
+func (q *delayQueueImpl) PushBack(el interface{}) {
+	q.PushAt(el, time.Now())
+}
+
+// popDue removes the head if it is due. Must be called with the lock held
+func (q *delayQueueImpl) popDue() (interface{}, bool) {
+	item, ok := q.h.peek()
+	if !ok || item.value.deadline.After(time.Now()) {
+		return nil, false
+	}
+
+	q.h.popFront()
+	return item.value.el, true
+}
+
+func (q *delayQueueImpl) PopFront() interface{} {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	el, _ := q.popDue()
+	return el
+}
+
+func (q *delayQueueImpl) Take(ctx context.Context) (interface{}, error) {
+	q.mutex.Lock()
+	q.waiters++
+
+	defer func() {
+		q.waiters--
+		q.mutex.Unlock()
+	}()
+
+	for {
+		if el, ok := q.popDue(); ok {
+			return el, nil
+		}
+
+		if item, ok := q.h.peek(); ok {
+			q.arm(item.value.deadline)
+		}
+
+		wake := q.wake
+		q.mutex.Unlock()
+
+		select {
+		case <-wake:
+			q.mutex.Lock()
+		case <-ctx.Done():
+			q.mutex.Lock()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (q *delayQueueImpl) NextDeadline() (time.Time, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	item, ok := q.h.peek()
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return item.value.deadline, true
+}
+
+func (q *delayQueueImpl) Size() int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return q.h.Len()
+}
@@ -0,0 +1,130 @@
+package queue
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDelayPopFront(t *testing.T) {
+	assert := assert.New(t)
+	q := NewDelay()
+
+	q.PushAfter("later", 50*time.Millisecond)
+	q.PushBack("now")
+
+	assert.Equal(2, q.Size())
+	assert.Equal("now", q.PopFront())
+
+	// Not due yet
+	assert.Nil(q.PopFront())
+	assert.Equal(1, q.Size())
+
+	<-time.After(60 * time.Millisecond)
+	assert.Equal("later", q.PopFront())
+	assert.Nil(q.PopFront())
+}
+
+func TestDelayOrder(t *testing.T) {
+	assert := assert.New(t)
+	q := NewDelay()
+
+	now := time.Now()
+	q.PushAt("c", now.Add(-1*time.Millisecond))
+	q.PushAt("a", now.Add(-3*time.Millisecond))
+	q.PushAt("b1", now.Add(-2*time.Millisecond))
+	q.PushAt("b2", now.Add(-2*time.Millisecond))
+
+	deadline, ok := q.NextDeadline()
+	assert.True(ok)
+	assert.Equal(now.Add(-3*time.Millisecond), deadline)
+
+	for _, expected := range []string{"a", "b1", "b2", "c"} {
+		assert.Equal(expected, q.PopFront())
+	}
+
+	_, ok = q.NextDeadline()
+	assert.False(ok)
+}
+
+func TestDelayTake(t *testing.T) {
+	assert := assert.New(t)
+	q := NewDelay()
+
+	start := time.Now()
+	q.PushAfter("a", 30*time.Millisecond)
+
+	el, err := q.Take(context.Background())
+	assert.Nil(err)
+	assert.Equal("a", el)
+	assert.True(time.Since(start) >= 30*time.Millisecond)
+}
+
+func TestDelayTakeEarlierPush(t *testing.T) {
+	assert := assert.New(t)
+	q := NewDelay()
+
+	q.PushAfter("late", time.Hour)
+
+	go func() {
+		<-time.After(20 * time.Millisecond)
+		q.PushAfter("early", 10*time.Millisecond)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	el, err := q.Take(ctx)
+	assert.Nil(err)
+	assert.Equal("early", el)
+}
+
+func TestDelayTakeCtx(t *testing.T) {
+	assert := assert.New(t)
+	q := NewDelay()
+
+	q.PushAfter("late", time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := q.Take(ctx)
+	assert.Equal(context.DeadlineExceeded, err)
+	assert.Equal(1, q.Size())
+}
+
+func TestDelayTakeConcurrent(t *testing.T) {
+	assert := assert.New(t)
+	q := NewDelay()
+
+	const count = 50
+
+	for i := 0; i < count; i++ {
+		q.PushAfter(i, time.Duration(i%5)*time.Millisecond)
+	}
+
+	var wg sync.WaitGroup
+	results := make(chan interface{}, count)
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			el, err := q.Take(context.Background())
+			assert.Nil(err)
+			results <- el
+		}()
+	}
+
+	wg.Wait()
+	close(results)
+
+	seen := map[interface{}]bool{}
+	for el := range results {
+		seen[el] = true
+	}
+	assert.Equal(count, len(seen))
+	assert.Equal(0, q.Size())
+}
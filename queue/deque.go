@@ -0,0 +1,195 @@
+package queue
+
+// minDequeCapacity smallest backing array a deque will shrink to
+const minDequeCapacity = 16
+
+// deque is a growable ring buffer. It is not thread-safe, callers must hold their own lock
+type deque[T any] struct {
+	buf  []T
+	head int
+	size int
+
+	// minCap the deque never shrinks below this capacity
+	minCap int
+}
+
+func newDeque[T any](capacity int) *deque[T] {
+	capacity = nextPowerOfTwo(capacity)
+	if capacity < minDequeCapacity {
+		capacity = minDequeCapacity
+	}
+
+	return &deque[T]{
+		buf:    make([]T, capacity),
+		minCap: capacity,
+	}
+}
+
+func nextPowerOfTwo(n int) int {
+	p := 1
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
+
+// index maps a logical position to the backing array. len(buf) is always a power of two
+func (d *deque[T]) index(pos int) int {
+	return (d.head + pos) & (len(d.buf) - 1)
+}
+
+func (d *deque[T]) resize(capacity int) {
+	buf := make([]T, capacity)
+
+	if d.head+d.size <= len(d.buf) {
+		copy(buf, d.buf[d.head:d.head+d.size])
+	} else {
+		n := copy(buf, d.buf[d.head:])
+		copy(buf[n:], d.buf[:d.size-n])
+	}
+
+	d.buf = buf
+	d.head = 0
+}
+
+func (d *deque[T]) grow() {
+	if d.size == len(d.buf) {
+		d.resize(len(d.buf) << 1)
+	}
+}
+
+// shrink halves the backing array once the deque is drained to a quarter of its capacity
+func (d *deque[T]) shrink() {
+	if len(d.buf) > d.minCap && d.size <= len(d.buf)>>2 {
+		d.resize(len(d.buf) >> 1)
+	}
+}
+
+func (d *deque[T]) pushBack(el T) {
+	d.grow()
+	d.buf[d.index(d.size)] = el
+	d.size++
+}
+
+func (d *deque[T]) pushFront(el T) {
+	d.grow()
+	d.head = (d.head - 1) & (len(d.buf) - 1)
+	d.buf[d.head] = el
+	d.size++
+}
+
+func (d *deque[T]) popBack() (T, bool) {
+	var zero T
+	if d.size == 0 {
+		return zero, false
+	}
+
+	i := d.index(d.size - 1)
+	el := d.buf[i]
+	d.buf[i] = zero
+	d.size--
+
+	d.shrink()
+
+	return el, true
+}
+
+func (d *deque[T]) popFront() (T, bool) {
+	var zero T
+	if d.size == 0 {
+		return zero, false
+	}
+
+	el := d.buf[d.head]
+	d.buf[d.head] = zero
+	d.head = (d.head + 1) & (len(d.buf) - 1)
+	d.size--
+
+	d.shrink()
+
+	return el, true
+}
+
+func (d *deque[T]) get(pos int) (T, bool) {
+	if pos < 0 || pos >= d.size {
+		var zero T
+		return zero, false
+	}
+
+	return d.buf[d.index(pos)], true
+}
+
+func (d *deque[T]) len() int {
+	return d.size
+}
+
+func (d *deque[T]) snapshot() []T {
+	els := make([]T, d.size)
+	for i := range els {
+		els[i] = d.buf[d.index(i)]
+	}
+	return els
+}
+
+func (d *deque[T]) drain(n int) []T {
+	if n <= 0 || n > d.size {
+		n = d.size
+	}
+
+	els := make([]T, n)
+	for i := range els {
+		els[i], _ = d.popFront()
+	}
+	return els
+}
+
+func (d *deque[T]) each(fn func(i int, el T) bool) {
+	for i := 0; i < d.size; i++ {
+		if !fn(i, d.buf[d.index(i)]) {
+			return
+		}
+	}
+}
+
+func (d *deque[T]) clear() {
+	d.buf = make([]T, d.minCap)
+	d.head = 0
+	d.size = 0
+}
+
+// removeIf compacts the elements not matching "pred" towards the head, keeping their order
+func (d *deque[T]) removeIf(pred func(el T) bool) int {
+	var zero T
+
+	kept := 0
+	for i := 0; i < d.size; i++ {
+		el := d.buf[d.index(i)]
+		if pred(el) {
+			continue
+		}
+		d.buf[d.index(kept)] = el
+		kept++
+	}
+
+	for i := kept; i < d.size; i++ {
+		d.buf[d.index(i)] = zero
+	}
+
+	removed := d.size - kept
+	d.size = kept
+
+	for removed > 0 && len(d.buf) > d.minCap && d.size <= len(d.buf)>>2 {
+		d.shrink()
+	}
+
+	return removed
+}
+
+func (d *deque[T]) indexOf(pred func(el T) bool) int {
+	for i := 0; i < d.size; i++ {
+		if pred(d.buf[d.index(i)]) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,11 @@
+package queue
+
+import "errors"
+
+var (
+	// ErrQueueClosed tried to use a queue after closing it
+	ErrQueueClosed = errors.New("Queue is closed")
+
+	// ErrQueueFull tried to push to a full queue with the OverflowReject policy
+	ErrQueueFull = errors.New("Queue is full")
+)
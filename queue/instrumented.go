@@ -0,0 +1,328 @@
+package queue
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/GustavoKatel/asyncutils/queue/interfaces"
+)
+
+// InstrumentedQueue blocking queue that tracks its metrics and notifies changes
+type InstrumentedQueue[T any] interfaces.TypedInstrumentedQueue[T]
+
+// QueueStats counters of an instrumented queue
+type QueueStats = interfaces.QueueStats
+
+// QueueEvent change notification of an instrumented queue
+type QueueEvent = interfaces.QueueEvent
+
+var _ interfaces.TypedInstrumentedQueue[int] = &instrumentedQueueImpl[int]{}
+
+// stamped element with the time it was pushed
+type stamped[T any] struct {
+	el T
+	at time.Time
+}
+
+type instrumentedQueueImpl[T any] struct {
+	q       *boundedQueueImpl[stamped[T]]
+	onEvict func(el T)
+
+	mutex       *sync.RWMutex
+	stats       QueueStats
+	subscribers map[chan QueueEvent]struct{}
+}
+
+// NewInstrumented creates a blocking queue like NewBoundedWithPolicy that also tracks how many elements
+// went through it, how long they waited and notifies every change to its subscribers
+func NewInstrumented[T any](capacity int, policy OverflowPolicy, onEvict func(el T)) InstrumentedQueue[T] {
+	w := &instrumentedQueueImpl[T]{
+		onEvict: onEvict,
+
+		mutex:       &sync.RWMutex{},
+		subscribers: map[chan QueueEvent]struct{}{},
+	}
+
+	w.q = NewBoundedWithPolicy(capacity, policy, w.evicted).(*boundedQueueImpl[stamped[T]])
+
+	return w
+}
+
+// publish sends an event to all subscribers. Must be called with the lock held
+func (w *instrumentedQueueImpl[T]) publish(ev QueueEvent) {
+	for ch := range w.subscribers {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+func (w *instrumentedQueueImpl[T]) pushed() {
+	size := w.q.Size()
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.stats.Enqueued++
+	if size > w.stats.HighWaterMark {
+		w.stats.HighWaterMark = size
+	}
+
+	w.publish(QueueEvent{Type: interfaces.QueueEventPush, Time: time.Now()})
+}
+
+func (w *instrumentedQueueImpl[T]) popped(entries ...stamped[T]) {
+	if len(entries) == 0 {
+		return
+	}
+
+	now := time.Now()
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	for _, entry := range entries {
+		wait := now.Sub(entry.at)
+
+		w.stats.Dequeued++
+		w.stats.TotalWait += wait
+		if wait > w.stats.MaxWait {
+			w.stats.MaxWait = wait
+		}
+
+		w.publish(QueueEvent{Type: interfaces.QueueEventPop, Time: now, Wait: wait})
+	}
+}
+
+func (w *instrumentedQueueImpl[T]) removed(entries []stamped[T]) {
+	now := time.Now()
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	for _, entry := range entries {
+		w.stats.Removed++
+		w.publish(QueueEvent{Type: interfaces.QueueEventRemove, Time: now, Wait: now.Sub(entry.at)})
+	}
+}
+
+func (w *instrumentedQueueImpl[T]) evicted(entry stamped[T]) {
+	now := time.Now()
+
+	w.mutex.Lock()
+	w.stats.Evicted++
+	w.publish(QueueEvent{Type: interfaces.QueueEventEvict, Time: now, Wait: now.Sub(entry.at)})
+	w.mutex.Unlock()
+
+	if w.onEvict != nil {
+		w.onEvict(entry.el)
+	}
+}
+
+func (w *instrumentedQueueImpl[T]) push(ctx context.Context, el T, front bool) error {
+	added, err := w.q.push(ctx, stamped[T]{el: el, at: time.Now()}, front, true)
+	if added {
+		w.pushed()
+	}
+
+	return err
+}
+
+// pushOrEvict is used by the pushes that can't return errors, like boundedQueueImpl.PushBack
+func (w *instrumentedQueueImpl[T]) pushOrEvict(el T, front bool) {
+	entry := stamped[T]{el: el, at: time.Now()}
+
+	added, err := w.q.push(context.Background(), entry, front, true)
+	if added {
+		w.pushed()
+	} else if err == ErrQueueFull {
+		w.evicted(entry)
+	}
+}
+
+func (w *instrumentedQueueImpl[T]) PushBack(el T) {
+	w.pushOrEvict(el, false)
+}
+
+func (w *instrumentedQueueImpl[T]) PushFront(el T) {
+	w.pushOrEvict(el, true)
+}
+
+func (w *instrumentedQueueImpl[T]) PushBackAll(els ...T) {
+	for _, el := range els {
+		w.pushOrEvict(el, false)
+	}
+}
+
+func (w *instrumentedQueueImpl[T]) PushBackCtx(ctx context.Context, el T) error {
+	return w.push(ctx, el, false)
+}
+
+func (w *instrumentedQueueImpl[T]) PushFrontCtx(ctx context.Context, el T) error {
+	return w.push(ctx, el, true)
+}
+
+func (w *instrumentedQueueImpl[T]) PushBackTimeout(el T, d time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+
+	return w.push(ctx, el, false)
+}
+
+func (w *instrumentedQueueImpl[T]) TryPushBack(el T) bool {
+	added, _ := w.q.push(context.Background(), stamped[T]{el: el, at: time.Now()}, false, false)
+	if added {
+		w.pushed()
+	}
+
+	return added
+}
+
+func (w *instrumentedQueueImpl[T]) PopBack() (T, bool) {
+	entry, ok := w.q.PopBack()
+	if ok {
+		w.popped(entry)
+	}
+
+	return entry.el, ok
+}
+
+func (w *instrumentedQueueImpl[T]) PopFront() (T, bool) {
+	entry, ok := w.q.PopFront()
+	if ok {
+		w.popped(entry)
+	}
+
+	return entry.el, ok
+}
+
+func (w *instrumentedQueueImpl[T]) TryPopFront() (T, bool) {
+	return w.PopFront()
+}
+
+func (w *instrumentedQueueImpl[T]) PopFrontCtx(ctx context.Context) (T, error) {
+	entry, err := w.q.PopFrontCtx(ctx)
+	if err == nil {
+		w.popped(entry)
+	}
+
+	return entry.el, err
+}
+
+func (w *instrumentedQueueImpl[T]) PopFrontTimeout(d time.Duration) (T, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+
+	return w.PopFrontCtx(ctx)
+}
+
+func (w *instrumentedQueueImpl[T]) DrainTo(n int) []T {
+	entries := w.q.DrainTo(n)
+	w.popped(entries...)
+
+	els := make([]T, len(entries))
+	for i, entry := range entries {
+		els[i] = entry.el
+	}
+
+	return els
+}
+
+func (w *instrumentedQueueImpl[T]) Get(pos int) (T, bool) {
+	entry, ok := w.q.Get(pos)
+	return entry.el, ok
+}
+
+func (w *instrumentedQueueImpl[T]) Size() int {
+	return w.q.Size()
+}
+
+func (w *instrumentedQueueImpl[T]) Capacity() int {
+	return w.q.Capacity()
+}
+
+func (w *instrumentedQueueImpl[T]) Snapshot() []T {
+	entries := w.q.Snapshot()
+
+	els := make([]T, len(entries))
+	for i, entry := range entries {
+		els[i] = entry.el
+	}
+
+	return els
+}
+
+func (w *instrumentedQueueImpl[T]) Range(fn func(i int, el T) bool) {
+	w.q.Range(func(i int, entry stamped[T]) bool {
+		return fn(i, entry.el)
+	})
+}
+
+func (w *instrumentedQueueImpl[T]) Clear() {
+	w.Remove(func(el T) bool { return true })
+}
+
+func (w *instrumentedQueueImpl[T]) Remove(pred func(el T) bool) int {
+	removed := []stamped[T]{}
+
+	count := w.q.Remove(func(entry stamped[T]) bool {
+		if pred(entry.el) {
+			removed = append(removed, entry)
+			return true
+		}
+		return false
+	})
+
+	w.removed(removed)
+
+	return count
+}
+
+func (w *instrumentedQueueImpl[T]) IndexOf(pred func(el T) bool) int {
+	return w.q.IndexOf(func(entry stamped[T]) bool {
+		return pred(entry.el)
+	})
+}
+
+func (w *instrumentedQueueImpl[T]) OldestAge() time.Duration {
+	entry, ok := w.q.Get(0)
+	if !ok {
+		return 0
+	}
+
+	return time.Since(entry.at)
+}
+
+func (w *instrumentedQueueImpl[T]) Stats() QueueStats {
+	w.mutex.RLock()
+	stats := w.stats
+	w.mutex.RUnlock()
+
+	stats.Size = w.q.Size()
+	stats.OldestAge = w.OldestAge()
+
+	return stats
+}
+
+func (w *instrumentedQueueImpl[T]) Subscribe(buffer int) (<-chan QueueEvent, func()) {
+	ch := make(chan QueueEvent, buffer)
+
+	w.mutex.Lock()
+	w.subscribers[ch] = struct{}{}
+	w.mutex.Unlock()
+
+	once := &sync.Once{}
+	unsubscribe := func() {
+		once.Do(func() {
+			w.mutex.Lock()
+			defer w.mutex.Unlock()
+
+			delete(w.subscribers, ch)
+			close(ch)
+		})
+	}
+
+	return ch, unsubscribe
+}
@@ -0,0 +1,113 @@
+package queue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GustavoKatel/asyncutils/queue/interfaces"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstrumentedStats(t *testing.T) {
+	assert := assert.New(t)
+	q := NewInstrumented[int](0, OverflowBlock, nil)
+
+	q.PushBack(1)
+	q.PushBack(2)
+	q.PushBackAll(3, 4)
+
+	<-time.After(20 * time.Millisecond)
+	assert.True(q.OldestAge() >= 20*time.Millisecond)
+
+	el, ok := q.PopFront()
+	assert.True(ok)
+	assert.Equal(1, el)
+
+	assert.Equal([]int{2}, q.DrainTo(1))
+	assert.Equal(1, q.Remove(func(el int) bool { return el == 3 }))
+
+	stats := q.Stats()
+	assert.Equal(uint64(4), stats.Enqueued)
+	assert.Equal(uint64(2), stats.Dequeued)
+	assert.Equal(uint64(1), stats.Removed)
+	assert.Equal(1, stats.Size)
+	assert.Equal(4, stats.HighWaterMark)
+	assert.True(stats.MaxWait >= 20*time.Millisecond)
+	assert.True(stats.TotalWait >= 40*time.Millisecond)
+	assert.True(stats.OldestAge >= 20*time.Millisecond)
+
+	q.Clear()
+	assert.Equal(time.Duration(0), q.OldestAge())
+	assert.Equal(uint64(2), q.Stats().Removed)
+}
+
+func TestInstrumentedEvict(t *testing.T) {
+	assert := assert.New(t)
+
+	evicted := []int{}
+	q := NewInstrumented(1, OverflowDropOldest, func(el int) { evicted = append(evicted, el) })
+
+	q.PushBack(1)
+	assert.Nil(q.PushBackCtx(context.Background(), 2))
+
+	assert.Equal([]int{1}, evicted)
+	assert.Equal([]int{2}, q.Snapshot())
+
+	stats := q.Stats()
+	assert.Equal(uint64(2), stats.Enqueued)
+	assert.Equal(uint64(1), stats.Evicted)
+	assert.Equal(1, stats.HighWaterMark)
+}
+
+func TestInstrumentedReject(t *testing.T) {
+	assert := assert.New(t)
+	q := NewInstrumented[int](1, OverflowReject, nil)
+
+	assert.True(q.TryPushBack(1))
+	assert.False(q.TryPushBack(2))
+	assert.Equal(ErrQueueFull, q.PushBackCtx(context.Background(), 3))
+
+	stats := q.Stats()
+	assert.Equal(uint64(1), stats.Enqueued)
+	assert.Equal(uint64(0), stats.Evicted)
+}
+
+func TestInstrumentedSubscribe(t *testing.T) {
+	assert := assert.New(t)
+	q := NewInstrumented[string](1, OverflowDropNewest, nil)
+
+	events, unsubscribe := q.Subscribe(10)
+
+	q.PushBack("a")
+	q.PushBack("b")
+	_, err := q.PopFrontCtx(context.Background())
+	assert.Nil(err)
+
+	assert.Equal(interfaces.QueueEventPush, (<-events).Type)
+	assert.Equal(interfaces.QueueEventEvict, (<-events).Type)
+	assert.Equal(interfaces.QueueEventPop, (<-events).Type)
+
+	unsubscribe()
+	unsubscribe()
+
+	q.PushBack("c")
+	_, ok := <-events
+	assert.False(ok)
+}
+
+func TestInstrumentedSlowSubscriber(t *testing.T) {
+	assert := assert.New(t)
+	q := NewInstrumented[int](0, OverflowBlock, nil)
+
+	events, unsubscribe := q.Subscribe(1)
+	defer unsubscribe()
+
+	// Never blocks even though nobody reads the events
+	for i := 0; i < 10; i++ {
+		q.PushBack(i)
+	}
+
+	assert.Equal(1, len(events))
+	assert.Equal(10, q.Size())
+}
@@ -1,5 +1,10 @@
 package interfaces
 
+import (
+	"context"
+	"time"
+)
+
 // Queue thread-safe queue implementation
 type Queue interface {
 	PushBack(el interface{})
@@ -15,3 +20,250 @@ type Queue interface {
 
 	Size() int
 }
+
+// BulkQueue Queue with bulk operations and consistent iteration
+type BulkQueue interface {
+	Queue
+
+	// PushBackAll pushes all elements to the back of the queue, keeping their order
+	PushBackAll(els ...interface{})
+
+	// DrainTo removes up to "n" elements from the head of the queue. n <= 0 removes all of them
+	DrainTo(n int) []interface{}
+
+	// Snapshot returns a copy of all elements, from head to back
+	Snapshot() []interface{}
+
+	// Range calls fn for each element, from head to back, until it returns false.
+	// The queue is locked while iterating, so fn must not call the queue
+	Range(fn func(i int, el interface{}) bool)
+
+	// Clear removes all elements
+	Clear()
+
+	// Remove removes all elements matching "pred" and returns how many were removed
+	Remove(pred func(el interface{}) bool) int
+
+	// IndexOf returns the position of the first element matching "pred" or -1
+	IndexOf(pred func(el interface{}) bool) int
+}
+
+// TypedQueue thread-safe type-safe queue implementation
+type TypedQueue[T any] interface {
+	PushBack(el T)
+	PushFront(el T)
+
+	// PopBack removes an element from the back of the queue. Returns false if queue is empty
+	PopBack() (T, bool)
+	// PopFront removes an element from the head of the queue. Returns false if queue is empty
+	PopFront() (T, bool)
+
+	// Get returns an element in position "pos" or false if "pos" is out of bounds
+	Get(pos int) (T, bool)
+
+	Size() int
+
+	// PushBackAll pushes all elements to the back of the queue, keeping their order
+	PushBackAll(els ...T)
+
+	// DrainTo removes up to "n" elements from the head of the queue. n <= 0 removes all of them
+	DrainTo(n int) []T
+
+	// Snapshot returns a copy of all elements, from head to back
+	Snapshot() []T
+
+	// Range calls fn for each element, from head to back, until it returns false.
+	// The queue is locked while iterating, so fn must not call the queue
+	Range(fn func(i int, el T) bool)
+
+	// Clear removes all elements
+	Clear()
+
+	// Remove removes all elements matching "pred" and returns how many were removed
+	Remove(pred func(el T) bool) int
+
+	// IndexOf returns the position of the first element matching "pred" or -1
+	IndexOf(pred func(el T) bool) int
+}
+
+// TypedBlockingQueue thread-safe type-safe queue with an optional capacity.
+// By default PushBack and PushFront block while the queue is full, other overflow policies may reject or drop elements
+type TypedBlockingQueue[T any] interface {
+	TypedQueue[T]
+
+	// PushBackCtx pushes an element to the back of the queue, waiting for free space or ctx to be done.
+	// Returns ErrQueueFull if the overflow policy rejected it
+	PushBackCtx(ctx context.Context, el T) error
+	// PushFrontCtx pushes an element to the head of the queue, waiting for free space or ctx to be done.
+	// Returns ErrQueueFull if the overflow policy rejected it
+	PushFrontCtx(ctx context.Context, el T) error
+	// PopFrontCtx removes an element from the head of the queue, waiting for one to be available or ctx to be done
+	PopFrontCtx(ctx context.Context) (T, error)
+
+	// PushBackTimeout same as PushBackCtx but gives up after "d"
+	PushBackTimeout(el T, d time.Duration) error
+	// PopFrontTimeout same as PopFrontCtx but gives up after "d"
+	PopFrontTimeout(d time.Duration) (T, error)
+
+	// TryPushBack pushes an element to the back of the queue without waiting. Returns false if it was not added
+	TryPushBack(el T) bool
+	// TryPopFront removes an element from the head of the queue. Returns false if queue is empty
+	TryPopFront() (T, bool)
+
+	// Capacity returns the maximum number of elements in the queue, zero means unbounded
+	Capacity() int
+}
+
+// PriorityHandle references an element pushed to a PriorityQueue
+type PriorityHandle interface {
+	// Value returns the element referenced by this handle
+	Value() interface{}
+}
+
+// PriorityQueue thread-safe queue sorted by priority. PopFront returns the element with the highest priority,
+// elements with the same priority keep their insertion order
+type PriorityQueue interface {
+	Queue
+
+	// Push adds an element and returns a handle that can be used to update or remove it later
+	Push(el interface{}) PriorityHandle
+
+	// Peek returns the element at the head of the queue without removing it. Returns nil if queue is empty
+	Peek() interface{}
+
+	// Update replaces the element referenced by "h" and moves it to its new position.
+	// Returns false if the element is no longer in the queue
+	Update(h PriorityHandle, el interface{}) bool
+
+	// Remove removes the element referenced by "h". Returns false if the element is no longer in the queue
+	Remove(h PriorityHandle) bool
+}
+
+// FIFOQueue thread-safe first-in first-out queue. It is the subset of Queue that lock-free implementations can provide
+type FIFOQueue interface {
+	PushBack(el interface{})
+
+	// PopFront removes an element from the head of the queue. Returns nil if queue is empty
+	PopFront() interface{}
+
+	// Size returns the number of elements in the queue. It may be stale under concurrent use
+	Size() int
+}
+
+// Codec serializes queue elements to be stored by persistent queues
+type Codec interface {
+	Encode(el interface{}) ([]byte, error)
+	Decode(data []byte) (interface{}, error)
+}
+
+// PersistentQueue Queue that survives process restarts. Elements are written to disk as they are pushed and popped.
+// Write errors are sticky: the failing operation is not applied and the error is returned by Err, Sync and Close.
+// Elements the codec can't encode are not added, without affecting the rest of the queue
+type PersistentQueue interface {
+	Queue
+
+	// PushBackErr same as PushBack but returns the error if the element can't be encoded or written
+	PushBackErr(el interface{}) error
+
+	// PushFrontErr same as PushFront but returns the error if the element can't be encoded or written
+	PushFrontErr(el interface{}) error
+
+	// Err returns the first error found while writing to disk
+	Err() error
+
+	// Sync flushes the queue file to stable storage
+	Sync() error
+
+	// Compact rewrites the queue file keeping only the elements currently in the queue
+	Compact() error
+
+	// Close syncs and closes the queue file. The queue must not be used afterwards
+	Close() error
+}
+
+// DelayQueue thread-safe queue whose elements only become visible after their deadline.
+// Elements are returned in deadline order, elements with the same deadline keep their insertion order
+type DelayQueue interface {
+	// PushAt adds an element that becomes visible at "deadline"
+	PushAt(el interface{}, deadline time.Time)
+
+	// PushAfter adds an element that becomes visible after "d"
+	PushAfter(el interface{}, d time.Duration)
+
+	// PushBack adds an element that is visible right away
+	PushBack(el interface{})
+
+	// PopFront removes the element with the earliest deadline if it is due. Returns nil otherwise
+	PopFront() interface{}
+
+	// Take removes the element with the earliest deadline, waiting for it to be due or ctx to be done
+	Take(ctx context.Context) (interface{}, error)
+
+	// NextDeadline returns the earliest deadline in the queue. Returns false if queue is empty
+	NextDeadline() (time.Time, bool)
+
+	// Size returns the number of elements in the queue, due or not
+	Size() int
+}
+
+// QueueStats counters of an instrumented queue
+type QueueStats struct {
+	// Enqueued number of elements added to the queue
+	Enqueued uint64
+	// Dequeued number of elements popped or drained from the queue
+	Dequeued uint64
+	// Evicted number of elements dropped by the overflow policy
+	Evicted uint64
+	// Removed number of elements removed with Remove or Clear
+	Removed uint64
+
+	// Size current number of elements
+	Size int
+	// HighWaterMark largest number of elements the queue held at once
+	HighWaterMark int
+
+	// OldestAge time the element at the head of the queue has been waiting
+	OldestAge time.Duration
+	// TotalWait time spent in the queue by all dequeued elements
+	TotalWait time.Duration
+	// MaxWait longest time spent in the queue by a dequeued element
+	MaxWait time.Duration
+}
+
+// QueueEventType kind of change in an instrumented queue
+type QueueEventType int
+
+const (
+	// QueueEventPush an element was added
+	QueueEventPush QueueEventType = iota
+	// QueueEventPop an element was popped or drained
+	QueueEventPop
+	// QueueEventEvict an element was dropped by the overflow policy
+	QueueEventEvict
+	// QueueEventRemove an element was removed with Remove or Clear
+	QueueEventRemove
+)
+
+// QueueEvent change notification of an instrumented queue
+type QueueEvent struct {
+	Type QueueEventType
+	// Time when the change happened
+	Time time.Time
+	// Wait time the element spent in the queue, zero for pushes
+	Wait time.Duration
+}
+
+// TypedInstrumentedQueue blocking queue that tracks its metrics and notifies changes
+type TypedInstrumentedQueue[T any] interface {
+	TypedBlockingQueue[T]
+
+	// Stats returns a snapshot of the queue metrics
+	Stats() QueueStats
+
+	// OldestAge returns the time the element at the head of the queue has been waiting, zero if queue is empty
+	OldestAge() time.Duration
+
+	// Subscribe returns a channel receiving every change in the queue and a function to unsubscribe.
+	// Events are dropped if the channel buffer is full, so slow subscribers never block the queue
+	Subscribe(buffer int) (<-chan QueueEvent, func())
+}
@@ -0,0 +1,100 @@
+package queue
+
+import (
+	"sync/atomic"
+
+	"github.com/GustavoKatel/asyncutils/queue/interfaces"
+)
+
+var _ interfaces.FIFOQueue = &lockFreeQueueImpl{}
+
+// Every Queue can be used where a FIFOQueue is expected
+var _ interfaces.FIFOQueue = interfaces.Queue(nil)
+
+type lockFreeNode struct {
+	value interface{}
+	next  atomic.Pointer[lockFreeNode]
+}
+
+// lockFreeQueueImpl is an unbounded Michael-Scott queue. head always points to a dummy node,
+// the first element of the queue is head.next
+type lockFreeQueueImpl struct {
+	head atomic.Pointer[lockFreeNode]
+	tail atomic.Pointer[lockFreeNode]
+	size atomic.Int64
+}
+
+// NewLockFree creates a new unbounded lock-free multi-producer multi-consumer queue.
+// Only PushBack and PopFront can be implemented without locks, so it provides FIFOQueue instead of Queue
+func NewLockFree() interfaces.FIFOQueue {
+	q := &lockFreeQueueImpl{}
+
+	dummy := &lockFreeNode{}
+	q.head.Store(dummy)
+	q.tail.Store(dummy)
+
+	return q
+}
+
+func (q *lockFreeQueueImpl) PushBack(el interface{}) {
+	node := &lockFreeNode{value: el}
+
+	for {
+		tail := q.tail.Load()
+		next := tail.next.Load()
+
+		if tail != q.tail.Load() {
+			continue
+		}
+
+		if next != nil {
+			// tail is lagging behind, help the other producer move it forward
+			q.tail.CompareAndSwap(tail, next)
+			continue
+		}
+
+		if tail.next.CompareAndSwap(nil, node) {
+			q.tail.CompareAndSwap(tail, node)
+			q.size.Add(1)
+			return
+		}
+	}
+}
+
+func (q *lockFreeQueueImpl) PopFront() interface{} {
+	for {
+		head := q.head.Load()
+		tail := q.tail.Load()
+		next := head.next.Load()
+
+		if head != q.head.Load() {
+			continue
+		}
+
+		if next == nil {
+			return nil
+		}
+
+		if head == tail {
+			q.tail.CompareAndSwap(tail, next)
+			continue
+		}
+
+		if q.head.CompareAndSwap(head, next) {
+			// next is the new dummy node, only the winner of the swap reads its value
+			el := next.value
+			next.value = nil
+			q.size.Add(-1)
+			return el
+		}
+	}
+}
+
+func (q *lockFreeQueueImpl) Size() int {
+	size := q.size.Load()
+	if size < 0 {
+		return 0
+	}
+
+	return int(size)
+}
@@ -0,0 +1,135 @@
+package queue
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/GustavoKatel/asyncutils/queue/interfaces"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockFreePushPop(t *testing.T) {
+	assert := assert.New(t)
+	q := NewLockFree()
+
+	assert.Equal(0, q.Size())
+	assert.Nil(q.PopFront())
+
+	q.PushBack("abc")
+	q.PushBack("back")
+
+	assert.Equal(2, q.Size())
+
+	assert.Equal("abc", q.PopFront())
+	assert.Equal("back", q.PopFront())
+	assert.Nil(q.PopFront())
+	assert.Equal(0, q.Size())
+}
+
+func TestLockFreeStress(t *testing.T) {
+	assert := assert.New(t)
+	q := NewLockFree()
+
+	const producers = 8
+	const consumers = 8
+	const perProducer = 5000
+
+	var producersWg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		producersWg.Add(1)
+		go func(p int) {
+			defer producersWg.Done()
+			for i := 0; i < perProducer; i++ {
+				q.PushBack(p*perProducer + i)
+			}
+		}(p)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		producersWg.Wait()
+		close(done)
+	}()
+
+	seen := make([][]int, consumers)
+	var consumersWg sync.WaitGroup
+	for c := 0; c < consumers; c++ {
+		consumersWg.Add(1)
+		go func(c int) {
+			defer consumersWg.Done()
+			last := make(map[int]int)
+			for {
+				el := q.PopFront()
+				if el == nil {
+					select {
+					case <-done:
+						if q.Size() == 0 {
+							return
+						}
+					default:
+						runtime.Gosched()
+					}
+					continue
+				}
+
+				v := el.(int)
+				producer := v / perProducer
+
+				// Elements from the same producer must come out in order
+				if prev, ok := last[producer]; ok {
+					assert.Less(prev, v)
+				}
+				last[producer] = v
+
+				seen[c] = append(seen[c], v)
+			}
+		}(c)
+	}
+
+	consumersWg.Wait()
+
+	all := make(map[int]bool)
+	for _, s := range seen {
+		for _, v := range s {
+			assert.False(all[v], "element %d popped twice", v)
+			all[v] = true
+		}
+	}
+
+	assert.Equal(producers*perProducer, len(all))
+	assert.Equal(0, q.Size())
+}
+
+func benchmarkFIFOParallel(b *testing.B, newQueue func() interfaces.FIFOQueue) {
+	for _, goroutines := range []int{1, 4, 16, 64} {
+		b.Run(fmt.Sprintf("goroutines-%d", goroutines), func(b *testing.B) {
+			q := newQueue()
+
+			var wg sync.WaitGroup
+			perGoroutine := b.N/goroutines + 1
+
+			b.ResetTimer()
+			for g := 0; g < goroutines; g++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := 0; i < perGoroutine; i++ {
+						q.PushBack(i)
+						q.PopFront()
+					}
+				}()
+			}
+			wg.Wait()
+		})
+	}
+}
+
+func BenchmarkLockFreeParallel(b *testing.B) {
+	benchmarkFIFOParallel(b, NewLockFree)
+}
+
+func BenchmarkSliceParallel(b *testing.B) {
+	benchmarkFIFOParallel(b, func() interfaces.FIFOQueue { return New() })
+}
@@ -0,0 +1,516 @@
+package queue
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/GustavoKatel/asyncutils/queue/interfaces"
+)
+
+// SyncPolicy controls when a persistent queue flushes its file to stable storage
+type SyncPolicy int
+
+const (
+	// SyncAlways syncs after every operation
+	SyncAlways SyncPolicy = iota
+	// SyncInterval syncs periodically, see WithSyncInterval
+	SyncInterval
+	// SyncNever leaves syncing to the operating system. Operations survive process crashes but not power loss
+	SyncNever
+)
+
+const (
+	persistentLogName = "queue.log"
+
+	opPushBack  byte = 1
+	opPushFront byte = 2
+	opPopBack   byte = 3
+	opPopFront  byte = 4
+
+	// op (1) + payload length (4) + crc32 of op and payload (4)
+	recordHeaderSize = 9
+)
+
+type persistentOptions struct {
+	syncPolicy       SyncPolicy
+	syncInterval     time.Duration
+	compactThreshold int
+}
+
+// PersistentOption configures a persistent queue
+type PersistentOption func(opts *persistentOptions)
+
+// WithSyncPolicy sets when the queue file is synced. Defaults to SyncAlways
+func WithSyncPolicy(policy SyncPolicy) PersistentOption {
+	return func(opts *persistentOptions) {
+		opts.syncPolicy = policy
+	}
+}
+
+// WithSyncInterval sets SyncInterval as the sync policy, syncing every "d". Defaults to one second
+func WithSyncInterval(d time.Duration) PersistentOption {
+	return func(opts *persistentOptions) {
+		opts.syncPolicy = SyncInterval
+		opts.syncInterval = d
+	}
+}
+
+// WithCompactThreshold sets how many records the queue file holds before it is compacted.
+// The file is only compacted when most of its records refer to elements that were already popped. Defaults to 1024
+func WithCompactThreshold(records int) PersistentOption {
+	return func(opts *persistentOptions) {
+		opts.compactThreshold = records
+	}
+}
+
+var _ interfaces.PersistentQueue = &persistentQueueImpl{}
+
+type persistentQueueImpl struct {
+	mutex *sync.Mutex
+
+	d     *deque[interface{}]
+	codec Codec
+	opts  persistentOptions
+
+	path    string
+	file    *os.File
+	records int
+	dirty   bool
+	err     error
+	closed  bool
+
+	stopSync chan struct{}
+	syncWg   *sync.WaitGroup
+}
+
+// NewPersistent opens the queue stored in "dir", creating it if needed, and recovers its elements.
+// Elements are serialized with "codec". A record that was only partially written, like after a crash,
+// is discarded together with anything after it
+func NewPersistent(dir string, codec Codec, opts ...PersistentOption) (interfaces.PersistentQueue, error) {
+	options := persistentOptions{
+		syncPolicy:       SyncAlways,
+		syncInterval:     time.Second,
+		compactThreshold: 1024,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	q := &persistentQueueImpl{
+		mutex: &sync.Mutex{},
+
+		d:     newDeque[interface{}](0),
+		codec: codec,
+		opts:  options,
+
+		path: filepath.Join(dir, persistentLogName),
+
+		stopSync: make(chan struct{}),
+		syncWg:   &sync.WaitGroup{},
+	}
+
+	if err := q.recover(); err != nil {
+		return nil, err
+	}
+
+	if options.syncPolicy == SyncInterval && options.syncInterval > 0 {
+		q.syncWg.Add(1)
+		go q.syncLoop()
+	}
+
+	return q, nil
+}
+
+// recover replays the queue file and truncates it after the last complete record
+func (q *persistentQueueImpl) recover() error {
+	file, err := os.OpenFile(q.path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	r := bufio.NewReader(file)
+	var offset int64
+
+	for {
+		op, payload, err := readRecord(r, info.Size()-offset)
+		if err != nil {
+			break
+		}
+
+		if err := q.apply(op, payload); err != nil {
+			file.Close()
+			return err
+		}
+
+		offset += int64(recordHeaderSize + len(payload))
+		q.records++
+	}
+
+	if err := file.Truncate(offset); err != nil {
+		file.Close()
+		return err
+	}
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		file.Close()
+		return err
+	}
+
+	q.file = file
+	return nil
+}
+
+func (q *persistentQueueImpl) apply(op byte, payload []byte) error {
+	switch op {
+	case opPushBack, opPushFront:
+		el, err := q.codec.Decode(payload)
+		if err != nil {
+			return err
+		}
+
+		if op == opPushBack {
+			q.d.pushBack(el)
+		} else {
+			q.d.pushFront(el)
+		}
+	case opPopBack:
+		q.d.popBack()
+	case opPopFront:
+		q.d.popFront()
+	}
+
+	return nil
+}
+
+func encodeRecord(op byte, payload []byte) []byte {
+	record := make([]byte, recordHeaderSize+len(payload))
+	record[0] = op
+	binary.LittleEndian.PutUint32(record[1:5], uint32(len(payload)))
+	copy(record[recordHeaderSize:], payload)
+
+	crc := crc32.NewIEEE()
+	crc.Write(record[:1])
+	crc.Write(payload)
+	binary.LittleEndian.PutUint32(record[5:9], crc.Sum32())
+
+	return record
+}
+
+var errCorruptRecord = errors.New("corrupt record")
+
+// readRecord reads the next record, "remaining" is the number of bytes left in the file
+func readRecord(r io.Reader, remaining int64) (byte, []byte, error) {
+	header := make([]byte, recordHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, nil, err
+	}
+
+	size := int64(binary.LittleEndian.Uint32(header[1:5]))
+	if size > remaining-recordHeaderSize {
+		return 0, nil, io.ErrUnexpectedEOF
+	}
+
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+
+	crc := crc32.NewIEEE()
+	crc.Write(header[:1])
+	crc.Write(payload)
+	if crc.Sum32() != binary.LittleEndian.Uint32(header[5:9]) {
+		return 0, nil, errCorruptRecord
+	}
+
+	return header[0], payload, nil
+}
+
+// write appends a record to the queue file. Must be called with the lock held
+func (q *persistentQueueImpl) write(op byte, payload []byte) error {
+	if _, err := q.file.Write(encodeRecord(op, payload)); err != nil {
+		q.err = err
+		return err
+	}
+
+	q.records++
+	q.dirty = true
+
+	return nil
+}
+
+// commit applies the sync policy and compacts the file if needed. Must be called with the lock held
+func (q *persistentQueueImpl) commit() {
+	if q.opts.syncPolicy == SyncAlways {
+		if err := q.sync(); err != nil {
+			return
+		}
+	}
+
+	q.maybeCompact()
+}
+
+func (q *persistentQueueImpl) sync() error {
+	if !q.dirty {
+		return nil
+	}
+
+	if err := q.file.Sync(); err != nil {
+		q.err = err
+		return err
+	}
+
+	q.dirty = false
+	return nil
+}
+
+func (q *persistentQueueImpl) syncLoop() {
+	defer q.syncWg.Done()
+
+	ticker := time.NewTicker(q.opts.syncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			q.mutex.Lock()
+			if !q.closed {
+				q.sync()
+			}
+			q.mutex.Unlock()
+		case <-q.stopSync:
+			return
+		}
+	}
+}
+
+func (q *persistentQueueImpl) maybeCompact() {
+	if q.opts.compactThreshold <= 0 || q.records < q.opts.compactThreshold || q.records < 2*q.d.len() {
+		return
+	}
+
+	q.compact()
+}
+
+// compact writes the current elements to a new file and atomically replaces the old one.
+// The old file is left untouched if anything fails, so errors here are not sticky
+func (q *persistentQueueImpl) compact() error {
+	tmpPath := q.path + ".tmp"
+
+	tmp, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	fail := func(err error) error {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+
+	w := bufio.NewWriter(tmp)
+	for _, el := range q.d.snapshot() {
+		payload, err := q.codec.Encode(el)
+		if err != nil {
+			return fail(err)
+		}
+
+		if _, err := w.Write(encodeRecord(opPushBack, payload)); err != nil {
+			return fail(err)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return fail(err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return fail(err)
+	}
+	if err := os.Rename(tmpPath, q.path); err != nil {
+		return fail(err)
+	}
+
+	// Make the rename itself durable
+	if dir, err := os.Open(filepath.Dir(q.path)); err == nil {
+		dir.Sync()
+		dir.Close()
+	}
+
+	q.file.Close()
+	q.file = tmp
+	q.records = q.d.len()
+	q.dirty = false
+
+	return nil
+}
+
+// push encodes and writes the element. Encode errors only reject this element, write errors are sticky
+func (q *persistentQueueImpl) push(el interface{}, op byte) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.closed {
+		return ErrQueueClosed
+	}
+	if q.err != nil {
+		return q.err
+	}
+
+	payload, err := q.codec.Encode(el)
+	if err != nil {
+		return err
+	}
+
+	if err := q.write(op, payload); err != nil {
+		return err
+	}
+
+	if op == opPushBack {
+		q.d.pushBack(el)
+	} else {
+		q.d.pushFront(el)
+	}
+
+	q.commit()
+
+	return nil
+}
+
+func (q *persistentQueueImpl) pop(op byte) interface{} {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.closed || q.err != nil || q.d.len() == 0 {
+		return nil
+	}
+
+	if err := q.write(op, nil); err != nil {
+		return nil
+	}
+
+	var el interface{}
+	if op == opPopBack {
+		el, _ = q.d.popBack()
+	} else {
+		el, _ = q.d.popFront()
+	}
+
+	q.commit()
+
+	return el
+}
+
+func (q *persistentQueueImpl) PushBack(el interface{}) {
+	q.push(el, opPushBack)
+}
+
+func (q *persistentQueueImpl) PushFront(el interface{}) {
+	q.push(el, opPushFront)
+}
+
+func (q *persistentQueueImpl) PushBackErr(el interface{}) error {
+	return q.push(el, opPushBack)
+}
+
+func (q *persistentQueueImpl) PushFrontErr(el interface{}) error {
+	return q.push(el, opPushFront)
+}
+
+func (q *persistentQueueImpl) PopBack() interface{} {
+	return q.pop(opPopBack)
+}
+
+func (q *persistentQueueImpl) PopFront() interface{} {
+	return q.pop(opPopFront)
+}
+
+func (q *persistentQueueImpl) Get(pos int) interface{} {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	el, _ := q.d.get(pos)
+	return el
+}
+
+func (q *persistentQueueImpl) Size() int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return q.d.len()
+}
+
+func (q *persistentQueueImpl) Err() error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return q.err
+}
+
+func (q *persistentQueueImpl) Sync() error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.closed {
+		return ErrQueueClosed
+	}
+	if q.err != nil {
+		return q.err
+	}
+
+	return q.sync()
+}
+
+func (q *persistentQueueImpl) Compact() error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.closed {
+		return ErrQueueClosed
+	}
+	if q.err != nil {
+		return q.err
+	}
+
+	return q.compact()
+}
+
+func (q *persistentQueueImpl) Close() error {
+	q.mutex.Lock()
+
+	if q.closed {
+		q.mutex.Unlock()
+		return ErrQueueClosed
+	}
+
+	err := q.err
+	if err == nil {
+		err = q.sync()
+	}
+	if closeErr := q.file.Close(); err == nil {
+		err = closeErr
+	}
+	q.closed = true
+
+	q.mutex.Unlock()
+
+	close(q.stopSync)
+	q.syncWg.Wait()
+
+	return err
+}
@@ -0,0 +1,226 @@
+package queue
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPersistentRecover(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+
+	q, err := NewPersistent(dir, StringCodec{})
+	assert.Nil(err)
+
+	q.PushBack("a")
+	q.PushBack("b")
+	q.PushBack("c")
+	q.PushFront("front")
+
+	assert.Equal("front", q.PopFront())
+	assert.Equal("c", q.PopBack())
+	assert.Nil(q.Close())
+
+	q, err = NewPersistent(dir, StringCodec{})
+	assert.Nil(err)
+	defer q.Close()
+
+	assert.Equal(2, q.Size())
+	assert.Equal("a", q.Get(0))
+	assert.Equal("b", q.Get(1))
+}
+
+func TestPersistentTornWrite(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, persistentLogName)
+
+	q, err := NewPersistent(dir, StringCodec{}, WithSyncPolicy(SyncNever))
+	assert.Nil(err)
+
+	q.PushBack("a")
+	q.PushBack("b")
+	assert.Nil(q.Close())
+
+	info, err := os.Stat(path)
+	assert.Nil(err)
+
+	// Simulate a crash in the middle of writing the last record
+	assert.Nil(os.Truncate(path, info.Size()-1))
+
+	q, err = NewPersistent(dir, StringCodec{})
+	assert.Nil(err)
+
+	assert.Equal(1, q.Size())
+	assert.Equal("a", q.Get(0))
+
+	// The partial record was dropped, new records are appended after the last complete one
+	q.PushBack("c")
+	assert.Nil(q.Close())
+
+	q, err = NewPersistent(dir, StringCodec{})
+	assert.Nil(err)
+	defer q.Close()
+
+	assert.Equal(2, q.Size())
+	assert.Equal("a", q.Get(0))
+	assert.Equal("c", q.Get(1))
+}
+
+func TestPersistentCorruptRecord(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, persistentLogName)
+
+	q, err := NewPersistent(dir, StringCodec{})
+	assert.Nil(err)
+
+	q.PushBack("a")
+	q.PushBack("b")
+	assert.Nil(q.Close())
+
+	// Garbage appended by a torn write, with a length that goes past the end of the file
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o644)
+	assert.Nil(err)
+	_, err = f.Write([]byte{opPushBack, 0xff, 0xff, 0xff, 0x7f, 1, 2, 3, 4, 'x'})
+	assert.Nil(err)
+	assert.Nil(f.Close())
+
+	// Flip a payload byte of the second record so its checksum no longer matches
+	data, err := os.ReadFile(path)
+	assert.Nil(err)
+	data[2*recordHeaderSize+1] = 'x'
+	assert.Nil(os.WriteFile(path, data, 0o644))
+
+	q, err = NewPersistent(dir, StringCodec{})
+	assert.Nil(err)
+	defer q.Close()
+
+	assert.Equal(1, q.Size())
+	assert.Equal("a", q.Get(0))
+
+	info, err := os.Stat(path)
+	assert.Nil(err)
+	assert.Equal(int64(recordHeaderSize+1), info.Size())
+}
+
+func TestPersistentCompact(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, persistentLogName)
+
+	q, err := NewPersistent(dir, StringCodec{}, WithSyncPolicy(SyncNever), WithCompactThreshold(10))
+	assert.Nil(err)
+
+	for i := 0; i < 100; i++ {
+		q.PushBack("el")
+		q.PopFront()
+	}
+	q.PushBack("last")
+
+	info, err := os.Stat(path)
+	assert.Nil(err)
+	assert.True(info.Size() < 10*(recordHeaderSize+2))
+
+	assert.Nil(q.Compact())
+
+	info, err = os.Stat(path)
+	assert.Nil(err)
+	assert.Equal(int64(recordHeaderSize+len("last")), info.Size())
+
+	assert.Nil(q.Close())
+
+	q, err = NewPersistent(dir, StringCodec{})
+	assert.Nil(err)
+	defer q.Close()
+
+	assert.Equal(1, q.Size())
+	assert.Equal("last", q.PopFront())
+}
+
+func TestPersistentSyncInterval(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+
+	q, err := NewPersistent(dir, StringCodec{}, WithSyncInterval(10*time.Millisecond))
+	assert.Nil(err)
+
+	q.PushBack("a")
+	<-time.After(30 * time.Millisecond)
+
+	assert.Nil(q.Sync())
+	assert.Nil(q.Close())
+	assert.Equal(ErrQueueClosed, q.Close())
+	assert.Equal(ErrQueueClosed, q.Sync())
+}
+
+// failingCodec fails to encode the "bad" string
+type failingCodec struct {
+	StringCodec
+}
+
+var errBadElement = errors.New("bad element")
+
+func (c failingCodec) Encode(el interface{}) ([]byte, error) {
+	if el == "bad" {
+		return nil, errBadElement
+	}
+
+	return c.StringCodec.Encode(el)
+}
+
+func TestPersistentEncodeError(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+
+	q, err := NewPersistent(dir, failingCodec{})
+	assert.Nil(err)
+
+	q.PushBack("a")
+	assert.Equal(errBadElement, q.PushBackErr("bad"))
+	q.PushBack("bad")
+	assert.Equal(errBadElement, q.PushFrontErr("bad"))
+
+	// Only the element that failed is rejected
+	assert.Nil(q.Err())
+	assert.Nil(q.PushBackErr("b"))
+	assert.Equal(2, q.Size())
+	assert.Equal("a", q.PopFront())
+
+	assert.Nil(q.Close())
+	assert.Equal(ErrQueueClosed, q.PushBackErr("c"))
+
+	q, err = NewPersistent(dir, failingCodec{})
+	assert.Nil(err)
+	defer q.Close()
+
+	assert.Equal(1, q.Size())
+	assert.Equal("b", q.PopFront())
+}
+
+func TestPersistentJSONCodec(t *testing.T) {
+	type job struct {
+		Name string
+	}
+
+	assert := assert.New(t)
+	dir := t.TempDir()
+	codec := JSONCodec{New: func() interface{} { return &job{} }}
+
+	q, err := NewPersistent(dir, codec)
+	assert.Nil(err)
+
+	q.PushBack(&job{Name: "a"})
+	assert.Nil(q.Close())
+
+	q, err = NewPersistent(dir, codec)
+	assert.Nil(err)
+	defer q.Close()
+
+	assert.Equal(&job{Name: "a"}, q.PopFront())
+}
@@ -0,0 +1,146 @@
+package queue
+
+import (
+	"sync"
+
+	"github.com/GustavoKatel/asyncutils/queue/interfaces"
+)
+
+var _ interfaces.PriorityQueue = &priorityQueueImpl{}
+
+type priorityQueueImpl struct {
+	h     *priorityHeap[interface{}]
+	mutex *sync.RWMutex
+}
+
+type priorityHandle struct {
+	item *heapItem[interface{}]
+	q    *priorityQueueImpl
+}
+
+func (h *priorityHandle) Value() interface{} {
+	h.q.mutex.RLock()
+	defer h.q.mutex.RUnlock()
+
+	return h.item.value
+}
+
+// NewPriority creates a new priority queue. "less" reports whether "a" has a higher priority than "b".
+// PushBack adds an element after the ones with the same priority and PushFront before them
+func NewPriority(less func(a, b interface{}) bool) interfaces.PriorityQueue {
+	return &priorityQueueImpl{
+		h:     newPriorityHeap(less),
+		mutex: &sync.RWMutex{},
+	}
+}
+
+func (q *priorityQueueImpl) Push(el interface{}) interfaces.PriorityHandle {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return &priorityHandle{
+		item: q.h.pushBack(el),
+		q:    q,
+	}
+}
+
+func (q *priorityQueueImpl) PushBack(el interface{}) {
+	q.Push(el)
+}
+
+func (q *priorityQueueImpl) PushFront(el interface{}) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.h.pushFront(el)
+}
+
+// PopBack removes the element with the lowest priority
+func (q *priorityQueueImpl) PopBack() interface{} {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	item, ok := q.h.popBack()
+	if !ok {
+		return nil
+	}
+
+	return item.value
+}
+
+// PopFront removes the element with the highest priority
+func (q *priorityQueueImpl) PopFront() interface{} {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	item, ok := q.h.popFront()
+	if !ok {
+		return nil
+	}
+
+	return item.value
+}
+
+func (q *priorityQueueImpl) Peek() interface{} {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	item, ok := q.h.peek()
+	if !ok {
+		return nil
+	}
+
+	return item.value
+}
+
+// Get returns the element in position "pos" in priority order. It sorts a copy of the queue, O(n log n)
+func (q *priorityQueueImpl) Get(pos int) interface{} {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	if pos < 0 || pos >= q.h.Len() {
+		return nil
+	}
+
+	return q.h.sorted()[pos].value
+}
+
+func (q *priorityQueueImpl) handle(h interfaces.PriorityHandle) (*heapItem[interface{}], bool) {
+	ph, ok := h.(*priorityHandle)
+	if !ok || ph.q != q {
+		return nil, false
+	}
+
+	return ph.item, true
+}
+
+func (q *priorityQueueImpl) Update(h interfaces.PriorityHandle, el interface{}) bool {
+	item, ok := q.handle(h)
+	if !ok {
+		return false
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return q.h.update(item, el)
+}
+
+func (q *priorityQueueImpl) Remove(h interfaces.PriorityHandle) bool {
+	item, ok := q.handle(h)
+	if !ok {
+		return false
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return q.h.remove(item)
+}
+
+func (q *priorityQueueImpl) Size() int {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	return q.h.Len()
+}
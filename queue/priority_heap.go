@@ -0,0 +1,151 @@
+package queue
+
+import (
+	"container/heap"
+	"sort"
+)
+
+type heapItem[T any] struct {
+	value T
+	// seq breaks ties between elements with the same priority, lower goes first
+	seq int64
+	// index position in the heap, -1 once the item has been removed
+	index int
+}
+
+// priorityHeap is a binary heap with stable ordering among equal priorities.
+// It is not thread-safe, callers must hold their own lock
+type priorityHeap[T any] struct {
+	items []*heapItem[T]
+	less  func(a, b T) bool
+
+	nextBack  int64
+	nextFront int64
+}
+
+func newPriorityHeap[T any](less func(a, b T) bool) *priorityHeap[T] {
+	return &priorityHeap[T]{
+		items:     []*heapItem[T]{},
+		less:      less,
+		nextBack:  0,
+		nextFront: -1,
+	}
+}
+
+func (h *priorityHeap[T]) before(a, b *heapItem[T]) bool {
+	if h.less(a.value, b.value) {
+		return true
+	}
+	if h.less(b.value, a.value) {
+		return false
+	}
+	return a.seq < b.seq
+}
+
+// container/heap implementation
+
+func (h *priorityHeap[T]) Len() int { return len(h.items) }
+
+func (h *priorityHeap[T]) Less(i, j int) bool { return h.before(h.items[i], h.items[j]) }
+
+func (h *priorityHeap[T]) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.items[i].index = i
+	h.items[j].index = j
+}
+
+func (h *priorityHeap[T]) Push(x interface{}) {
+	item := x.(*heapItem[T])
+	item.index = len(h.items)
+	h.items = append(h.items, item)
+}
+
+func (h *priorityHeap[T]) Pop() interface{} {
+	n := len(h.items) - 1
+	item := h.items[n]
+	h.items[n] = nil
+	h.items = h.items[:n]
+	item.index = -1
+	return item
+}
+
+// pushBack adds an element after all the elements with the same priority
+func (h *priorityHeap[T]) pushBack(el T) *heapItem[T] {
+	item := &heapItem[T]{value: el, seq: h.nextBack}
+	h.nextBack++
+	heap.Push(h, item)
+	return item
+}
+
+// pushFront adds an element before all the elements with the same priority
+func (h *priorityHeap[T]) pushFront(el T) *heapItem[T] {
+	item := &heapItem[T]{value: el, seq: h.nextFront}
+	h.nextFront--
+	heap.Push(h, item)
+	return item
+}
+
+func (h *priorityHeap[T]) peek() (*heapItem[T], bool) {
+	if len(h.items) == 0 {
+		return nil, false
+	}
+	return h.items[0], true
+}
+
+func (h *priorityHeap[T]) popFront() (*heapItem[T], bool) {
+	if len(h.items) == 0 {
+		return nil, false
+	}
+	return heap.Pop(h).(*heapItem[T]), true
+}
+
+// popBack removes the element with the lowest priority. The last one is always a leaf
+func (h *priorityHeap[T]) popBack() (*heapItem[T], bool) {
+	if len(h.items) == 0 {
+		return nil, false
+	}
+
+	last := len(h.items) / 2
+	for i := last + 1; i < len(h.items); i++ {
+		if h.before(h.items[last], h.items[i]) {
+			last = i
+		}
+	}
+
+	return heap.Remove(h, last).(*heapItem[T]), true
+}
+
+func (h *priorityHeap[T]) contains(item *heapItem[T]) bool {
+	return item.index >= 0 && item.index < len(h.items) && h.items[item.index] == item
+}
+
+func (h *priorityHeap[T]) update(item *heapItem[T], el T) bool {
+	if !h.contains(item) {
+		return false
+	}
+
+	item.value = el
+	heap.Fix(h, item.index)
+	return true
+}
+
+func (h *priorityHeap[T]) remove(item *heapItem[T]) bool {
+	if !h.contains(item) {
+		return false
+	}
+
+	heap.Remove(h, item.index)
+	return true
+}
+
+// sorted returns the items in priority order
+func (h *priorityHeap[T]) sorted() []*heapItem[T] {
+	items := make([]*heapItem[T], len(h.items))
+	copy(items, h.items)
+
+	sort.Slice(items, func(i, j int) bool {
+		return h.before(items[i], items[j])
+	})
+
+	return items
+}
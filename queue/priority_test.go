@@ -0,0 +1,97 @@
+package queue
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type priorityJob struct {
+	name     string
+	priority int
+}
+
+func byPriority(a, b interface{}) bool {
+	return a.(*priorityJob).priority > b.(*priorityJob).priority
+}
+
+func TestPriorityOrder(t *testing.T) {
+	assert := assert.New(t)
+	q := NewPriority(func(a, b interface{}) bool {
+		return a.(int) < b.(int)
+	})
+
+	for _, el := range []int{5, 1, 4, 2, 3} {
+		q.PushBack(el)
+	}
+
+	assert.Equal(5, q.Size())
+	assert.Equal(1, q.Peek())
+	assert.Equal(1, q.Get(0))
+	assert.Equal(3, q.Get(2))
+	assert.Nil(q.Get(5))
+
+	assert.Equal(5, q.PopBack())
+	for i := 1; i <= 4; i++ {
+		assert.Equal(i, q.PopFront())
+	}
+
+	assert.Nil(q.PopFront())
+	assert.Nil(q.PopBack())
+	assert.Nil(q.Peek())
+}
+
+func TestPriorityStable(t *testing.T) {
+	assert := assert.New(t)
+	q := NewPriority(byPriority)
+
+	q.PushBack(&priorityJob{"a", 1})
+	q.PushBack(&priorityJob{"b", 2})
+	q.PushBack(&priorityJob{"c", 1})
+	q.PushBack(&priorityJob{"d", 2})
+	q.PushFront(&priorityJob{"e", 2})
+	q.PushBack(&priorityJob{"f", 1})
+
+	names := []string{}
+	for q.Size() > 0 {
+		names = append(names, q.PopFront().(*priorityJob).name)
+	}
+
+	assert.Equal([]string{"e", "b", "d", "a", "c", "f"}, names)
+}
+
+func TestPriorityUpdateRemove(t *testing.T) {
+	assert := assert.New(t)
+	q := NewPriority(byPriority)
+
+	a := q.Push(&priorityJob{"a", 1})
+	b := q.Push(&priorityJob{"b", 2})
+	c := q.Push(&priorityJob{"c", 3})
+
+	assert.Equal("c", q.Peek().(*priorityJob).name)
+
+	assert.True(q.Update(a, &priorityJob{"a", 10}))
+	assert.Equal("a", q.Peek().(*priorityJob).name)
+	assert.Equal(10, a.Value().(*priorityJob).priority)
+
+	assert.True(q.Remove(c))
+	assert.False(q.Remove(c))
+	assert.False(q.Update(c, &priorityJob{"c", 20}))
+
+	assert.Equal("a", q.PopFront().(*priorityJob).name)
+	assert.False(q.Remove(a))
+
+	assert.Equal("b", q.PopFront().(*priorityJob).name)
+	assert.False(q.Update(b, &priorityJob{"b", 1}))
+	assert.Equal(0, q.Size())
+}
+
+func TestPriorityForeignHandle(t *testing.T) {
+	assert := assert.New(t)
+	q1 := NewPriority(byPriority)
+	q2 := NewPriority(byPriority)
+
+	h := q1.Push(&priorityJob{"a", 1})
+	assert.False(q2.Remove(h))
+	assert.Equal(1, q1.Size())
+}
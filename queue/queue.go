@@ -1,84 +1,76 @@
 package queue
 
 import (
-	"sync"
-
 	"github.com/GustavoKatel/asyncutils/queue/interfaces"
 )
 
 type Queue interfaces.Queue
 
-var _ interfaces.Queue = &queueImpl{}
+var _ interfaces.BulkQueue = &queueImpl{}
 
+// queueImpl adapts a type-safe queue to the interface{} based interfaces.Queue
 type queueImpl struct {
-	q     []interface{}
-	mutex *sync.RWMutex
+	q TypedQueue[interface{}]
 }
 
 // New creates a new queue
-func New() interfaces.Queue {
+func New() interfaces.BulkQueue {
 	return &queueImpl{
-		q:     []interface{}{},
-		mutex: &sync.RWMutex{},
+		q: NewTyped[interface{}](),
 	}
 }
 
 func (q *queueImpl) PushBack(el interface{}) {
-	q.mutex.Lock()
-	defer q.mutex.Unlock()
-
-	q.q = append(q.q, el)
+	q.q.PushBack(el)
 }
 
 func (q *queueImpl) PushFront(el interface{}) {
-	q.mutex.Lock()
-	defer q.mutex.Unlock()
-
-	q.q = append([]interface{}{el}, q.q...)
+	q.q.PushFront(el)
 }
 
 func (q *queueImpl) PopBack() interface{} {
-	q.mutex.Lock()
-	defer q.mutex.Unlock()
-
-	if len(q.q) == 0 {
-		return nil
-	}
-
-	el := q.q[len(q.q)-1]
-	q.q = q.q[:len(q.q)-1]
-
+	el, _ := q.q.PopBack()
 	return el
 }
 
 func (q *queueImpl) PopFront() interface{} {
-	q.mutex.Lock()
-	defer q.mutex.Unlock()
+	el, _ := q.q.PopFront()
+	return el
+}
 
-	if len(q.q) == 0 {
-		return nil
-	}
+func (q *queueImpl) Get(pos int) interface{} {
+	el, _ := q.q.Get(pos)
+	return el
+}
 
-	el := q.q[0]
-	q.q = q.q[1:]
+func (q *queueImpl) Size() int {
+	return q.q.Size()
+}
 
-	return el
+func (q *queueImpl) PushBackAll(els ...interface{}) {
+	q.q.PushBackAll(els...)
 }
 
-func (q *queueImpl) Get(pos int) interface{} {
-	q.mutex.RLock()
-	defer q.mutex.RUnlock()
+func (q *queueImpl) DrainTo(n int) []interface{} {
+	return q.q.DrainTo(n)
+}
 
-	if pos >= len(q.q) {
-		return nil
-	}
+func (q *queueImpl) Snapshot() []interface{} {
+	return q.q.Snapshot()
+}
 
-	return q.q[pos]
+func (q *queueImpl) Range(fn func(i int, el interface{}) bool) {
+	q.q.Range(fn)
 }
 
-func (q *queueImpl) Size() int {
-	q.mutex.RLock()
-	defer q.mutex.RUnlock()
+func (q *queueImpl) Clear() {
+	q.q.Clear()
+}
+
+func (q *queueImpl) Remove(pred func(el interface{}) bool) int {
+	return q.q.Remove(pred)
+}
 
-	return len(q.q)
+func (q *queueImpl) IndexOf(pred func(el interface{}) bool) int {
+	return q.q.IndexOf(pred)
 }
@@ -3,9 +3,23 @@ package queue
 import (
 	"testing"
 
+	"github.com/GustavoKatel/asyncutils/queue/interfaces"
 	"github.com/stretchr/testify/assert"
 )
 
+func TestQueueType(t *testing.T) {
+	assert := assert.New(t)
+
+	// queue.Queue is still the interface{} based queue
+	var q Queue = New()
+	q.PushBack(1)
+
+	var typed TypedQueue[int] = NewTyped[int]()
+	typed.PushBack(1)
+
+	assert.Equal(q.Size(), typed.Size())
+}
+
 func TestPushBack(t *testing.T) {
 	assert := assert.New(t)
 	q := New()
@@ -102,3 +116,51 @@ func TestGetNil(t *testing.T) {
 	assert.Nil(q.Get(1))
 	assert.Nil(q.Get(2))
 }
+
+func testBulk(t *testing.T, q interfaces.BulkQueue) {
+	assert := assert.New(t)
+
+	q.PushBackAll(1, 2, 3, 4, 5, 6)
+	assert.Equal(6, q.Size())
+	assert.Equal([]interface{}{1, 2, 3, 4, 5, 6}, q.Snapshot())
+
+	isEven := func(el interface{}) bool { return el.(int)%2 == 0 }
+
+	assert.Equal(1, q.IndexOf(isEven))
+	assert.Equal(-1, q.IndexOf(func(el interface{}) bool { return el.(int) > 6 }))
+
+	visited := []interface{}{}
+	q.Range(func(i int, el interface{}) bool {
+		visited = append(visited, el)
+		return i < 2
+	})
+	assert.Equal([]interface{}{1, 2, 3}, visited)
+
+	assert.Equal(3, q.Remove(isEven))
+	assert.Equal([]interface{}{1, 3, 5}, q.Snapshot())
+
+	assert.Equal([]interface{}{1, 3}, q.DrainTo(2))
+	assert.Equal(1, q.Size())
+
+	q.PushBackAll(7, 9)
+	assert.Equal([]interface{}{5, 7, 9}, q.DrainTo(0))
+	assert.Equal(0, q.Size())
+
+	q.PushBackAll(1, 2)
+	q.Clear()
+	assert.Equal(0, q.Size())
+	assert.Equal([]interface{}{}, q.Snapshot())
+	assert.Nil(q.PopFront())
+}
+
+func TestBulk(t *testing.T) {
+	testBulk(t, New())
+}
+
+func TestBulkRing(t *testing.T) {
+	testBulk(t, NewRing(0))
+}
+
+func TestBulkBounded(t *testing.T) {
+	testBulk(t, &queueImpl{q: NewBounded[interface{}](10)})
+}
@@ -0,0 +1,124 @@
+package queue
+
+import (
+	"sync"
+
+	"github.com/GustavoKatel/asyncutils/queue/interfaces"
+)
+
+var _ interfaces.TypedQueue[int] = &ringQueueImpl[int]{}
+
+type ringQueueImpl[T any] struct {
+	d     *deque[T]
+	mutex *sync.RWMutex
+}
+
+// NewRing creates a new queue backed by a growable ring buffer.
+// "capacity" is the initial size of the buffer, the queue grows past it when needed
+// and shrinks back as it drains
+func NewRing(capacity int) interfaces.BulkQueue {
+	return &queueImpl{
+		q: NewTypedRing[interface{}](capacity),
+	}
+}
+
+// NewTypedRing creates a new type-safe queue backed by a growable ring buffer
+func NewTypedRing[T any](capacity int) TypedQueue[T] {
+	return &ringQueueImpl[T]{
+		d:     newDeque[T](capacity),
+		mutex: &sync.RWMutex{},
+	}
+}
+
+func (q *ringQueueImpl[T]) PushBack(el T) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.d.pushBack(el)
+}
+
+func (q *ringQueueImpl[T]) PushFront(el T) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.d.pushFront(el)
+}
+
+func (q *ringQueueImpl[T]) PopBack() (T, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return q.d.popBack()
+}
+
+func (q *ringQueueImpl[T]) PopFront() (T, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return q.d.popFront()
+}
+
+func (q *ringQueueImpl[T]) Get(pos int) (T, bool) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	return q.d.get(pos)
+}
+
+func (q *ringQueueImpl[T]) Size() int {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	return q.d.len()
+}
+
+func (q *ringQueueImpl[T]) PushBackAll(els ...T) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, el := range els {
+		q.d.pushBack(el)
+	}
+}
+
+func (q *ringQueueImpl[T]) DrainTo(n int) []T {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return q.d.drain(n)
+}
+
+func (q *ringQueueImpl[T]) Snapshot() []T {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	return q.d.snapshot()
+}
+
+func (q *ringQueueImpl[T]) Range(fn func(i int, el T) bool) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	q.d.each(fn)
+}
+
+func (q *ringQueueImpl[T]) Clear() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.d.clear()
+}
+
+func (q *ringQueueImpl[T]) Remove(pred func(el T) bool) int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return q.d.removeIf(pred)
+}
+
+func (q *ringQueueImpl[T]) IndexOf(pred func(el T) bool) int {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	return q.d.indexOf(pred)
+}
@@ -0,0 +1,135 @@
+package queue
+
+import (
+	"testing"
+
+	"github.com/GustavoKatel/asyncutils/queue/interfaces"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRingPushPop(t *testing.T) {
+	assert := assert.New(t)
+	q := NewRing(4)
+
+	assert.Equal(0, q.Size())
+
+	q.PushBack("abc")
+	q.PushBack("back")
+	q.PushFront("front")
+
+	assert.Equal(3, q.Size())
+	assert.Equal("front", q.Get(0))
+	assert.Equal("abc", q.Get(1))
+	assert.Equal("back", q.Get(2))
+	assert.Nil(q.Get(3))
+
+	assert.Equal("back", q.PopBack())
+	assert.Equal("front", q.PopFront())
+	assert.Equal("abc", q.PopFront())
+
+	assert.Nil(q.PopFront())
+	assert.Nil(q.PopBack())
+	assert.Equal(0, q.Size())
+}
+
+func TestRingWrapAround(t *testing.T) {
+	assert := assert.New(t)
+	q := NewTypedRing[int](minDequeCapacity)
+
+	// Move the head close to the end of the buffer so the next pushes wrap
+	for i := 0; i < minDequeCapacity-2; i++ {
+		q.PushBack(i)
+		q.PopFront()
+	}
+
+	for i := 0; i < minDequeCapacity; i++ {
+		q.PushBack(i)
+	}
+
+	for i := 0; i < minDequeCapacity; i++ {
+		el, ok := q.Get(i)
+		assert.True(ok)
+		assert.Equal(i, el)
+	}
+
+	for i := 0; i < minDequeCapacity; i++ {
+		el, ok := q.PopFront()
+		assert.True(ok)
+		assert.Equal(i, el)
+	}
+}
+
+func TestRingGrowAndShrink(t *testing.T) {
+	assert := assert.New(t)
+	q := NewTypedRing[int](0).(*ringQueueImpl[int])
+
+	assert.Equal(minDequeCapacity, len(q.d.buf))
+
+	for i := 0; i < 1000; i++ {
+		q.PushFront(i)
+	}
+
+	assert.Equal(1000, q.Size())
+	assert.Equal(1024, len(q.d.buf))
+
+	for i := 0; i < 1000; i++ {
+		el, ok := q.PopBack()
+		assert.True(ok)
+		assert.Equal(i, el)
+	}
+
+	assert.Equal(0, q.Size())
+	assert.Equal(minDequeCapacity, len(q.d.buf))
+}
+
+func TestRingRemoveWrapAround(t *testing.T) {
+	assert := assert.New(t)
+	q := NewTypedRing[int](minDequeCapacity)
+
+	for i := 0; i < minDequeCapacity-4; i++ {
+		q.PushBack(i)
+		q.PopFront()
+	}
+
+	q.PushBackAll(0, 1, 2, 3, 4, 5, 6, 7)
+
+	assert.Equal(4, q.Remove(func(el int) bool { return el%2 == 1 }))
+	assert.Equal([]int{0, 2, 4, 6}, q.Snapshot())
+	assert.Equal(2, q.IndexOf(func(el int) bool { return el == 4 }))
+}
+
+func benchmarkPushBackPopFront(b *testing.B, q interfaces.Queue) {
+	for i := 0; i < b.N; i++ {
+		q.PushBack(i)
+		q.PushBack(i)
+		q.PopFront()
+	}
+}
+
+func benchmarkPushFrontPopFront(b *testing.B, q interfaces.Queue) {
+	for i := 0; i < 1000; i++ {
+		q.PushBack(i)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		q.PushFront(i)
+		q.PopFront()
+	}
+}
+
+func BenchmarkSlicePushBackPopFront(b *testing.B) {
+	benchmarkPushBackPopFront(b, New())
+}
+
+func BenchmarkRingPushBackPopFront(b *testing.B) {
+	benchmarkPushBackPopFront(b, NewRing(0))
+}
+
+func BenchmarkSlicePushFrontPopFront(b *testing.B) {
+	benchmarkPushFrontPopFront(b, New())
+}
+
+func BenchmarkRingPushFrontPopFront(b *testing.B) {
+	benchmarkPushFrontPopFront(b, NewRing(0))
+}
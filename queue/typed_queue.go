@@ -0,0 +1,175 @@
+package queue
+
+import (
+	"sync"
+
+	"github.com/GustavoKatel/asyncutils/queue/interfaces"
+)
+
+// TypedQueue thread-safe type-safe queue
+type TypedQueue[T any] interfaces.TypedQueue[T]
+
+var _ interfaces.TypedQueue[int] = &typedQueueImpl[int]{}
+
+type typedQueueImpl[T any] struct {
+	q     []T
+	mutex *sync.RWMutex
+}
+
+// NewTyped creates a new type-safe queue
+func NewTyped[T any]() TypedQueue[T] {
+	return &typedQueueImpl[T]{
+		q:     []T{},
+		mutex: &sync.RWMutex{},
+	}
+}
+
+func (q *typedQueueImpl[T]) PushBack(el T) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.q = append(q.q, el)
+}
+
+func (q *typedQueueImpl[T]) PushFront(el T) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.q = append([]T{el}, q.q...)
+}
+
+func (q *typedQueueImpl[T]) PopBack() (T, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	var zero T
+	if len(q.q) == 0 {
+		return zero, false
+	}
+
+	el := q.q[len(q.q)-1]
+	q.q[len(q.q)-1] = zero
+	q.q = q.q[:len(q.q)-1]
+
+	return el, true
+}
+
+func (q *typedQueueImpl[T]) PopFront() (T, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	var zero T
+	if len(q.q) == 0 {
+		return zero, false
+	}
+
+	el := q.q[0]
+	q.q[0] = zero
+	q.q = q.q[1:]
+
+	return el, true
+}
+
+func (q *typedQueueImpl[T]) Get(pos int) (T, bool) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	if pos < 0 || pos >= len(q.q) {
+		var zero T
+		return zero, false
+	}
+
+	return q.q[pos], true
+}
+
+func (q *typedQueueImpl[T]) Size() int {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	return len(q.q)
+}
+
+func (q *typedQueueImpl[T]) PushBackAll(els ...T) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.q = append(q.q, els...)
+}
+
+func (q *typedQueueImpl[T]) DrainTo(n int) []T {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if n <= 0 || n > len(q.q) {
+		n = len(q.q)
+	}
+
+	els := make([]T, n)
+	copy(els, q.q)
+
+	var zero T
+	for i := 0; i < n; i++ {
+		q.q[i] = zero
+	}
+	q.q = q.q[n:]
+
+	return els
+}
+
+func (q *typedQueueImpl[T]) Snapshot() []T {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	els := make([]T, len(q.q))
+	copy(els, q.q)
+
+	return els
+}
+
+func (q *typedQueueImpl[T]) Range(fn func(i int, el T) bool) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for i, el := range q.q {
+		if !fn(i, el) {
+			return
+		}
+	}
+}
+
+func (q *typedQueueImpl[T]) Clear() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.q = []T{}
+}
+
+func (q *typedQueueImpl[T]) Remove(pred func(el T) bool) int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	kept := make([]T, 0, len(q.q))
+	for _, el := range q.q {
+		if !pred(el) {
+			kept = append(kept, el)
+		}
+	}
+
+	removed := len(q.q) - len(kept)
+	q.q = kept
+
+	return removed
+}
+
+func (q *typedQueueImpl[T]) IndexOf(pred func(el T) bool) int {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for i, el := range q.q {
+		if pred(el) {
+			return i
+		}
+	}
+
+	return -1
+}
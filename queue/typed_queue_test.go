@@ -0,0 +1,72 @@
+package queue
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypedPushPop(t *testing.T) {
+	assert := assert.New(t)
+	q := NewTyped[int]()
+
+	assert.Equal(0, q.Size())
+
+	q.PushBack(1)
+	q.PushBack(2)
+	q.PushFront(0)
+
+	assert.Equal(3, q.Size())
+
+	el, ok := q.PopFront()
+	assert.True(ok)
+	assert.Equal(0, el)
+
+	el, ok = q.PopBack()
+	assert.True(ok)
+	assert.Equal(2, el)
+
+	el, ok = q.PopFront()
+	assert.True(ok)
+	assert.Equal(1, el)
+
+	_, ok = q.PopFront()
+	assert.False(ok)
+	_, ok = q.PopBack()
+	assert.False(ok)
+}
+
+func TestTypedZeroValue(t *testing.T) {
+	assert := assert.New(t)
+	q := NewTyped[*int]()
+
+	q.PushBack(nil)
+	assert.Equal(1, q.Size())
+
+	el, ok := q.PopFront()
+	assert.True(ok)
+	assert.Nil(el)
+
+	el, ok = q.PopFront()
+	assert.False(ok)
+	assert.Nil(el)
+}
+
+func TestTypedGet(t *testing.T) {
+	assert := assert.New(t)
+	q := NewTyped[string]()
+
+	_, ok := q.Get(0)
+	assert.False(ok)
+
+	q.PushBack("abc")
+
+	el, ok := q.Get(0)
+	assert.True(ok)
+	assert.Equal("abc", el)
+
+	_, ok = q.Get(1)
+	assert.False(ok)
+	_, ok = q.Get(-1)
+	assert.False(ok)
+}
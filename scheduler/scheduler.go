@@ -7,6 +7,8 @@ import (
 
 	"github.com/GustavoKatel/asyncutils/executor"
 	executorIfaces "github.com/GustavoKatel/asyncutils/executor/interfaces"
+	"github.com/GustavoKatel/asyncutils/queue"
+	queueIfaces "github.com/GustavoKatel/asyncutils/queue/interfaces"
 	"github.com/GustavoKatel/asyncutils/scheduler/interfaces"
 )
 
@@ -27,6 +29,9 @@ type schedulerImpl struct {
 	// Maps tag to the last recorded call
 	throttleLast      executorIfaces.JobFn
 	throttleLastMutex *sync.RWMutex
+
+	// Holds the throttle delay of the pending throttled call until it is due
+	delays queueIfaces.DelayQueue
 }
 
 // New creates a new working channel
@@ -54,12 +59,15 @@ func NewWithContext(ctx context.Context) (interfaces.Scheduler, error) {
 
 		throttleLast:      nil,
 		throttleLastMutex: &sync.RWMutex{},
+
+		delays: queue.NewDelay(),
 	}
 
 	return aw, nil
 }
 
 func (aw *schedulerImpl) Start() error {
+	go aw.dispatch()
 	return aw.worker.Start()
 }
 
@@ -84,26 +92,31 @@ func (aw *schedulerImpl) waitAndSchedule(job executorIfaces.JobFn, diffDelay tim
 		return
 	}
 
-	go func() {
-		select {
-		case <-time.After(diffDelay):
-			aw.throttleLastMutex.Lock()
+	aw.delays.PushAfter(delay, diffDelay)
+}
 
-			if aw.throttleLast == nil {
-				aw.throttleLastMutex.Unlock()
-				return
-			}
+// dispatch posts the last throttled call once its delay is due
+func (aw *schedulerImpl) dispatch() {
+	for {
+		el, err := aw.delays.Take(aw.ctx)
+		if err != nil {
+			return
+		}
 
-			job := aw.throttleLast
-			aw.throttleLast = nil
+		aw.throttleLastMutex.Lock()
 
+		if aw.throttleLast == nil {
 			aw.throttleLastMutex.Unlock()
-
-			aw.PostThrottledJob(job, delay)
-		case <-aw.ctx.Done():
-			return
+			continue
 		}
-	}()
+
+		job := aw.throttleLast
+		aw.throttleLast = nil
+
+		aw.throttleLastMutex.Unlock()
+
+		aw.PostThrottledJob(job, el.(time.Duration))
+	}
 }
 
 func (aw *schedulerImpl) PostJob(job executorIfaces.JobFn) error {
@@ -0,0 +1,11 @@
+package semaphore
+
+import "errors"
+
+var (
+	// ErrTooManyPermits tried to acquire more permits than the semaphore size
+	ErrTooManyPermits = errors.New("Acquiring more permits than the semaphore size")
+
+	// ErrInvalidPermits tried to acquire less than one permit
+	ErrInvalidPermits = errors.New("Number of permits must be at least 1")
+)
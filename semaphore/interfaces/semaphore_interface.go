@@ -0,0 +1,32 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+)
+
+// Semaphore weighted counting semaphore. Waiters are served in arrival order
+type Semaphore interface {
+	// Acquire waits until n permits are available or ctx is done. Returns ctx.Err() if ctx was done first,
+	// ErrInvalidPermits if n is lower than 1 or ErrTooManyPermits if n is greater than the size
+	Acquire(ctx context.Context, n int) error
+
+	// AcquireTimeout waits until n permits are available or timeout. Returns true if the permits were acquired
+	AcquireTimeout(n int, d time.Duration) bool
+
+	// TryAcquire acquires n permits without waiting. Fails if there are other waiters, to keep the order,
+	// or if n is lower than 1
+	TryAcquire(n int) bool
+
+	// Release returns n permits. Panics if n is lower than 1 or more permits than held are released
+	Release(n int)
+
+	// Size total number of permits
+	Size() int
+
+	// Available number of permits not acquired
+	Available() int
+
+	// Waiting number of goroutines waiting in Acquire
+	Waiting() int
+}
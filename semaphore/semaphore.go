@@ -0,0 +1,166 @@
+package semaphore
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/GustavoKatel/asyncutils/semaphore/interfaces"
+)
+
+// Semaphore weighted counting semaphore
+type Semaphore interfaces.Semaphore
+
+var _ interfaces.Semaphore = &semaphoreImpl{}
+
+// New creates a new semaphore with "size" permits
+func New(size int) interfaces.Semaphore {
+	return &semaphoreImpl{
+		mutex:   &sync.Mutex{},
+		size:    size,
+		waiters: list.New(),
+	}
+}
+
+type waiter struct {
+	n int
+
+	// ready is closed once the permits are granted
+	ready chan struct{}
+}
+
+type semaphoreImpl struct {
+	mutex *sync.Mutex
+	size  int
+	cur   int
+
+	// waiters in arrival order
+	waiters *list.List
+}
+
+func (s *semaphoreImpl) Acquire(ctx context.Context, n int) error {
+	if n < 1 {
+		return ErrInvalidPermits
+	}
+	if n > s.size {
+		return ErrTooManyPermits
+	}
+
+	s.mutex.Lock()
+
+	if s.waiters.Len() == 0 && s.size-s.cur >= n {
+		s.cur += n
+		s.mutex.Unlock()
+		return nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		s.mutex.Unlock()
+		return err
+	}
+
+	w := &waiter{n: n, ready: make(chan struct{})}
+	el := s.waiters.PushBack(w)
+	s.mutex.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		select {
+		case <-w.ready:
+			// Granted in the meantime, the permits belong to this waiter now
+			return nil
+		default:
+		}
+
+		front := s.waiters.Front() == el
+		s.waiters.Remove(el)
+
+		// A large waiter at the front may have been holding back smaller ones
+		if front {
+			s.notifyWaiters()
+		}
+
+		return ctx.Err()
+	}
+}
+
+func (s *semaphoreImpl) AcquireTimeout(n int, d time.Duration) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+
+	return s.Acquire(ctx, n) == nil
+}
+
+func (s *semaphoreImpl) TryAcquire(n int) bool {
+	if n < 1 {
+		return false
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.waiters.Len() > 0 || s.size-s.cur < n {
+		return false
+	}
+
+	s.cur += n
+	return true
+}
+
+func (s *semaphoreImpl) Release(n int) {
+	if n < 1 {
+		panic("semaphore: released less than one permit")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.cur -= n
+	if s.cur < 0 {
+		panic("semaphore: released more permits than held")
+	}
+
+	s.notifyWaiters()
+}
+
+// notifyWaiters grants permits to the waiters in order while they fit. Must be called with the lock held
+func (s *semaphoreImpl) notifyWaiters() {
+	for {
+		el := s.waiters.Front()
+		if el == nil {
+			return
+		}
+
+		w := el.Value.(*waiter)
+		if s.size-s.cur < w.n {
+			// Not enough permits for the next waiter, the ones behind it have to wait too
+			return
+		}
+
+		s.cur += w.n
+		s.waiters.Remove(el)
+		close(w.ready)
+	}
+}
+
+func (s *semaphoreImpl) Size() int {
+	return s.size
+}
+
+func (s *semaphoreImpl) Available() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.size - s.cur
+}
+
+func (s *semaphoreImpl) Waiting() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.waiters.Len()
+}
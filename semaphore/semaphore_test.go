@@ -0,0 +1,165 @@
+package semaphore
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAcquireRelease(t *testing.T) {
+	assert := assert.New(t)
+	s := New(3)
+
+	assert.Nil(s.Acquire(context.Background(), 2))
+	assert.Equal(1, s.Available())
+
+	assert.True(s.TryAcquire(1))
+	assert.False(s.TryAcquire(1))
+	assert.False(s.AcquireTimeout(1, 10*time.Millisecond))
+
+	s.Release(3)
+	assert.Equal(3, s.Available())
+	assert.Equal(3, s.Size())
+
+	assert.Equal(ErrTooManyPermits, s.Acquire(context.Background(), 4))
+}
+
+func TestInvalidPermits(t *testing.T) {
+	assert := assert.New(t)
+	s := New(3)
+
+	assert.Nil(s.Acquire(context.Background(), 1))
+
+	assert.Equal(ErrInvalidPermits, s.Acquire(context.Background(), 0))
+	assert.Equal(ErrInvalidPermits, s.Acquire(context.Background(), -1))
+	assert.False(s.TryAcquire(0))
+	assert.Panics(func() { s.Release(0) })
+	assert.Panics(func() { s.Release(-1) })
+
+	assert.Equal(2, s.Available())
+}
+
+func TestAcquireContext(t *testing.T) {
+	assert := assert.New(t)
+	s := New(1)
+
+	assert.True(s.TryAcquire(1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.Equal(context.DeadlineExceeded, s.Acquire(ctx, 1))
+	assert.Equal(0, s.Waiting())
+
+	go func() {
+		<-time.After(10 * time.Millisecond)
+		s.Release(1)
+	}()
+
+	assert.True(s.AcquireTimeout(1, time.Second))
+}
+
+func TestAcquireFIFO(t *testing.T) {
+	assert := assert.New(t)
+	s := New(2)
+
+	assert.True(s.TryAcquire(2))
+
+	order := make(chan int, 3)
+	for i, n := range []int{2, 1, 1} {
+		go func(i, n int) {
+			assert.Nil(s.Acquire(context.Background(), n))
+			order <- i
+		}(i, n)
+
+		// Keep the arrival order
+		for s.Waiting() != i+1 {
+			<-time.After(time.Millisecond)
+		}
+	}
+
+	// The small waiters can't overtake the large one at the front
+	s.Release(1)
+	assert.False(s.TryAcquire(1))
+
+	s.Release(1)
+	assert.Equal(0, <-order)
+
+	s.Release(1)
+	assert.Equal(1, <-order)
+
+	s.Release(1)
+	assert.Equal(2, <-order)
+}
+
+func TestAcquireCancelFront(t *testing.T) {
+	assert := assert.New(t)
+	s := New(2)
+
+	assert.True(s.TryAcquire(1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error)
+	go func() {
+		result <- s.Acquire(ctx, 2)
+	}()
+
+	for s.Waiting() != 1 {
+		<-time.After(time.Millisecond)
+	}
+
+	small := make(chan error)
+	go func() {
+		small <- s.Acquire(context.Background(), 1)
+	}()
+
+	for s.Waiting() != 2 {
+		<-time.After(time.Millisecond)
+	}
+
+	// Removing the large waiter lets the small one through
+	cancel()
+	assert.Equal(context.Canceled, <-result)
+	assert.Nil(<-small)
+	assert.Equal(0, s.Available())
+}
+
+func TestSemaphoreContention(t *testing.T) {
+	assert := assert.New(t)
+	s := New(4)
+
+	var running, maxRunning int32
+	var wg sync.WaitGroup
+
+	for i := 0; i < 64; i++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+
+			if err := s.Acquire(context.Background(), n); err != nil {
+				return
+			}
+			defer s.Release(n)
+
+			current := atomic.AddInt32(&running, int32(n))
+			for {
+				old := atomic.LoadInt32(&maxRunning)
+				if current <= old || atomic.CompareAndSwapInt32(&maxRunning, old, current) {
+					break
+				}
+			}
+
+			<-time.After(time.Millisecond)
+			atomic.AddInt32(&running, -int32(n))
+		}(i%3 + 1)
+	}
+
+	wg.Wait()
+
+	assert.LessOrEqual(atomic.LoadInt32(&maxRunning), int32(4))
+	assert.Equal(4, s.Available())
+}